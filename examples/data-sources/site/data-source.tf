@@ -0,0 +1,4 @@
+data "googlesiteverification_site" "example" {
+  site_identifier = "example.com"
+  site_type       = "INET_DOMAIN"
+}
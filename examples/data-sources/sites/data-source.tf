@@ -0,0 +1,4 @@
+data "googlesiteverification_sites" "example" {
+  site_type         = "INET_DOMAIN"
+  identifier_suffix = "example.com"
+}
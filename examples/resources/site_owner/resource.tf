@@ -0,0 +1,4 @@
+resource "googlesiteverification_site_owner" "this" {
+  site_id = googlesiteverification_site_verification.this.id
+  owner   = "someone@example.com"
+}
@@ -0,0 +1,7 @@
+resource "googlesiteverification_site_owners" "this" {
+  site_id = googlesiteverification_site_verification.this.id
+  owners = [
+    "terraform@my-project.iam.gserviceaccount.com",
+    "someone@example.com",
+  ]
+}
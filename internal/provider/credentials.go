@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	credentialsEnvVar = "GOOGLE_CREDENTIALS"
+	accessTokenEnvVar = "GOOGLE_OAUTH_ACCESS_TOKEN"
+)
+
+// sourceScopes are requested for the credentials the provider is configured
+// with. They include cloud-platform so that the credentials can also be used
+// to impersonate service accounts.
+var sourceScopes = append([]string{"https://www.googleapis.com/auth/cloud-platform"}, clientScopes...)
+
+// defaultIdentity describes credentials whose principal cannot be determined locally.
+const defaultIdentity = "application default credentials"
+
+// accessTokenIdentity describes credentials built from a bare OAuth access token.
+const accessTokenIdentity = "OAuth access token"
+
+// credentialsIdentity returns a description of the principal behind creds
+// suitable for diagnostics.
+func credentialsIdentity(creds *google.Credentials) string {
+	if len(creds.JSON) == 0 {
+		if _, ok := creds.TokenSource.(staticAccessToken); ok {
+			return accessTokenIdentity
+		}
+		return defaultIdentity
+	}
+	var f struct {
+		ClientEmail string `json:"client_email"`
+		Type        string `json:"type"`
+	}
+	if err := json.Unmarshal(creds.JSON, &f); err != nil {
+		return defaultIdentity
+	}
+	switch {
+	case f.ClientEmail != "":
+		return f.ClientEmail
+	case f.Type == externalAccountType:
+		if cfg := parseExternalAccount(creds.JSON); cfg != nil {
+			return cfg.identity()
+		}
+		return f.Type
+	case f.Type != "":
+		return f.Type
+	}
+	return defaultIdentity
+}
+
+// detachedContext keeps the values of its parent, such as the logger, but is
+// never cancelled and has no deadline.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (c detachedContext) Done() <-chan struct{}       { return nil }
+func (c detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any           { return c.parent.Value(key) }
+
+// staticAccessToken is a token source for a user supplied OAuth access token.
+type staticAccessToken struct {
+	oauth2.TokenSource
+}
+
+// loadCredentials resolves the credentials the provider authenticates with.
+// Setting both the access_token and credentials attributes is an error;
+// otherwise the first of these that is set is used:
+//
+//  1. The access_token or credentials attribute.
+//  2. The GOOGLE_OAUTH_ACCESS_TOKEN environment variable.
+//  3. The GOOGLE_CREDENTIALS environment variable.
+//  4. Application default credentials.
+func loadCredentials(ctx context.Context, data *GoogleSiteVerificationProviderModel) (*google.Credentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Token sources keep the context they are built with and use it for every
+	// refresh, long after the request that configured the provider is done.
+	ctx = detachedContext{ctx}
+
+	if !data.AccessToken.IsNull() && !data.Credentials.IsNull() {
+		diags.AddAttributeError(path.Root("access_token"), "Conflicting credentials", "Only one of access_token and credentials may be set.")
+		return nil, diags
+	}
+
+	switch {
+	case !data.AccessToken.IsNull():
+		return accessTokenCredentials(path.Root("access_token"), "access_token", data.AccessToken.ValueString())
+	case !data.Credentials.IsNull():
+		return jsonCredentials(ctx, path.Root("credentials"), "credentials", data.Credentials.ValueString())
+	}
+
+	if token, ok := os.LookupEnv(accessTokenEnvVar); ok && token != "" {
+		tflog.Trace(ctx, "Using access token from environment", map[string]any{"env": accessTokenEnvVar})
+		return accessTokenCredentials(path.Empty(), accessTokenEnvVar, token)
+	}
+	if contents, ok := os.LookupEnv(credentialsEnvVar); ok && contents != "" {
+		tflog.Trace(ctx, "Using credentials from environment", map[string]any{"env": credentialsEnvVar})
+		return jsonCredentials(ctx, path.Empty(), credentialsEnvVar, contents)
+	}
+
+	tflog.Trace(ctx, "Attempting to load default credentials")
+	creds, err := google.FindDefaultCredentials(ctx, sourceScopes...)
+	if err != nil {
+		diags.AddError("Failed to load default credentials", err.Error())
+		return nil, diags
+	}
+	if err := checkExternalAccount(ctx, creds); err != nil {
+		diags.AddError("Invalid default credentials", err.Error())
+		return nil, diags
+	}
+	return creds, diags
+}
+
+func accessTokenCredentials(attr path.Path, source, token string) (*google.Credentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	token = strings.TrimSpace(token)
+	if token == "" {
+		addCredentialsError(&diags, attr, "Invalid access token", fmt.Sprintf("%s must not be empty.", source))
+		return nil, diags
+	}
+	return &google.Credentials{
+		TokenSource: staticAccessToken{oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: token,
+			TokenType:   "Bearer",
+		})},
+	}, diags
+}
+
+// jsonCredentials parses credentials given either as JSON content or as the path to a JSON file.
+func jsonCredentials(ctx context.Context, attr path.Path, source, value string) (*google.Credentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	contents := []byte(strings.TrimSpace(value))
+	if !strings.HasPrefix(string(contents), "{") {
+		b, err := os.ReadFile(string(contents))
+		if err != nil {
+			addCredentialsError(&diags, attr, "Failed to read credentials file", fmt.Sprintf("%s is neither JSON content nor a readable file: %s", source, err.Error()))
+			return nil, diags
+		}
+		contents = b
+	}
+	if cfg := parseExternalAccount(contents); cfg != nil {
+		if err := cfg.validate(); err != nil {
+			addCredentialsError(&diags, attr, "Invalid external account credentials", fmt.Sprintf("%s: %s", source, err.Error()))
+			return nil, diags
+		}
+	}
+	creds, err := google.CredentialsFromJSON(ctx, contents, sourceScopes...)
+	if err != nil {
+		addCredentialsError(&diags, attr, "Invalid credentials", fmt.Sprintf("Failed to parse %s: %s", source, err.Error()))
+		return nil, diags
+	}
+	if err := checkExternalAccount(ctx, creds); err != nil {
+		addCredentialsError(&diags, attr, "Invalid external account credentials", fmt.Sprintf("%s: %s", source, err.Error()))
+		return nil, diags
+	}
+	return creds, diags
+}
+
+func addCredentialsError(diags *diag.Diagnostics, attr path.Path, summary, detail string) {
+	if attr.Equal(path.Empty()) {
+		diags.AddError(summary, detail)
+		return
+	}
+	diags.AddAttributeError(attr, summary, detail)
+}
+
+// credentialsValue returns the value of an optional string attribute, or null when it is empty.
+func credentialsValue(v types.String) types.String {
+	if v.IsNull() || v.IsUnknown() || strings.TrimSpace(v.ValueString()) == "" {
+		return types.StringNull()
+	}
+	return v
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	dnsv2 "google.golang.org/api/dns/v2"
+)
+
+const (
+	defaultDNSTTL         = 60
+	dnsChangePollInterval = 2 * time.Second
+)
+
+// configDNSDefaults returns a model holding the DNS record settings planned
+// when dns_ttl and dns_record_name are not configured, with the same defaults
+// as Create. ok is false while an attribute they depend on is unknown.
+func configDNSDefaults(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) (data *SiteVerificationResourceModel, ok bool) {
+	data = &SiteVerificationResourceModel{
+		DNSTTL:        types.Int64Null(),
+		DNSRecordName: types.StringNull(),
+	}
+	diags.Append(config.GetAttribute(ctx, path.Root("verification_method"), &data.VerificationMethod)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("site_type"), &data.SiteType)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("site_identifier"), &data.SiteIdentifier)...)
+
+	if diags.HasError() || data.VerificationMethod.IsUnknown() || data.SiteType.IsUnknown() || data.SiteIdentifier.IsUnknown() {
+		return nil, false
+	}
+
+	if data.VerificationMethod.IsNull() {
+		data.VerificationMethod = types.StringValue("DNS_TXT")
+	}
+	if data.SiteType.IsNull() {
+		data.SiteType = types.StringValue("INET_DOMAIN")
+	}
+	data.SetDNSDefaults()
+	return data, true
+}
+
+// dnsTTLDefault plans the default TTL when dns_ttl is not configured, so that
+// a TTL changed outside Terraform is planned back to the default.
+type dnsTTLDefault struct{}
+
+func (m dnsTTLDefault) Description(ctx context.Context) string {
+	return fmt.Sprintf("defaults to %d for DNS verification methods", defaultDNSTTL)
+}
+
+func (m dnsTTLDefault) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m dnsTTLDefault) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if !req.ConfigValue.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if data, ok := configDNSDefaults(ctx, req.Config, &resp.Diagnostics); ok {
+		resp.PlanValue = data.DNSTTL
+	}
+}
+
+// dnsRecordNameDefault plans site_identifier as the name of the TXT record
+// when dns_record_name is not configured, so that removing it from the
+// configuration moves the record back.
+type dnsRecordNameDefault struct{}
+
+func (m dnsRecordNameDefault) Description(ctx context.Context) string {
+	return "defaults to site_identifier for the DNS_TXT verification method"
+}
+
+func (m dnsRecordNameDefault) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m dnsRecordNameDefault) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if data, ok := configDNSDefaults(ctx, req.Config, &resp.Diagnostics); ok {
+		resp.PlanValue = data.DNSRecordName
+	}
+}
+
+// dnsTTLValidator rejects TTLs Cloud DNS would not accept.
+type dnsTTLValidator struct{}
+
+func (v dnsTTLValidator) Description(ctx context.Context) string {
+	return "value must be a positive number of seconds"
+}
+
+func (v dnsTTLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v dnsTTLValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if ttl := req.ConfigValue.ValueInt64(); ttl <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid DNS TTL",
+			fmt.Sprintf("The TTL must be a positive number of seconds. Got %d.", ttl),
+		)
+	}
+}
+
+// rrdataEqual reports whether two values of a record of type rtype are the same.
+func rrdataEqual(rtype, a, b string) bool {
+	switch rtype {
+	case "TXT":
+		return txtValuesEqual(a, b)
+	case "CNAME":
+		return forceDot(a) == forceDot(b)
+	}
+	return a == b
+}
+
+func containsRrdata(rtype string, values []string, value string) bool {
+	for _, v := range values {
+		if rrdataEqual(rtype, v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameRrdatas reports whether a and b hold the same values, in any order.
+func sameRrdatas(rtype string, a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, value := range a {
+		if !containsRrdata(rtype, b, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// planRecordChange adds to change the edits that remove the values in remove
+// from, and add the values in add to, the record set named by record, while
+// preserving any other values it holds. The record set takes the TTL of record,
+// and is deleted when no values are left.
+func (r *SiteVerificationResource) planRecordChange(ctx context.Context, data *SiteVerificationResourceModel, change *dnsv2.Change, record *dnsv2.ResourceRecordSet, remove, add []string) error {
+	existing, err := r.Clients.DNS.ResourceRecordSets.Get(
+		data.Project.ValueString(),
+		"global",
+		data.ManagedZone.ValueString(),
+		record.Name,
+		record.Type,
+	).Context(ctx).Do()
+	if err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+		existing = nil
+	}
+
+	var rrdatas []string
+	if existing != nil {
+		for _, rrdata := range existing.Rrdatas {
+			if !containsRrdata(record.Type, remove, rrdata) {
+				rrdatas = append(rrdatas, rrdata)
+			}
+		}
+	}
+	for _, value := range add {
+		if !containsRrdata(record.Type, rrdatas, value) {
+			rrdatas = append(rrdatas, value)
+		}
+	}
+	if existing != nil && existing.Ttl == record.Ttl && sameRrdatas(record.Type, existing.Rrdatas, rrdatas) {
+		// The record set is already as wanted, for example when a token was
+		// only respelled.
+		return nil
+	}
+	if existing != nil {
+		change.Deletions = append(change.Deletions, existing)
+	}
+	if len(rrdatas) > 0 {
+		change.Additions = append(change.Additions, &dnsv2.ResourceRecordSet{
+			Name:    record.Name,
+			Rrdatas: rrdatas,
+			Ttl:     record.Ttl,
+			Type:    record.Type,
+		})
+	}
+	return nil
+}
+
+// applyDNSChange submits change to the managed zone of data and waits for
+// Cloud DNS to report it done. Cloud DNS applies the deletions and additions
+// of a change atomically, so the zone never serves a partial update.
+func (r *SiteVerificationResource) applyDNSChange(ctx context.Context, data *SiteVerificationResourceModel, change *dnsv2.Change) error {
+	if len(change.Additions) == 0 && len(change.Deletions) == 0 {
+		return nil
+	}
+	tflog.Trace(ctx, "Creating DNS change", map[string]any{
+		"zone":      data.ManagedZone.ValueString(),
+		"project":   data.Project.ValueString(),
+		"additions": change.Additions,
+		"deletions": change.Deletions,
+	})
+	gresp, err := r.Clients.DNS.Changes.Create(
+		data.Project.ValueString(),
+		"global",
+		data.ManagedZone.ValueString(),
+		change,
+	).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(dnsChangePollInterval)
+	defer ticker.Stop()
+	for gresp.Status != "done" {
+		tflog.Trace(ctx, "Waiting for DNS change", map[string]any{
+			"change": gresp.Id,
+			"status": gresp.Status,
+		})
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for DNS change %s to complete: %w", gresp.Id, ctx.Err())
+		case <-ticker.C:
+		}
+		gresp, err = r.Clients.DNS.Changes.Get(
+			data.Project.ValueString(),
+			"global",
+			data.ManagedZone.ValueString(),
+			gresp.Id,
+		).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to check status of DNS change: %w", err)
+		}
+	}
+	tflog.Trace(ctx, "DNS change done", map[string]any{
+		"change": gresp.Id,
+	})
+	return nil
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// siteVerificationConfig returns a site verification configuration with the
+// given attributes set and every other attribute null.
+func siteVerificationConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+	var current resource.SchemaResponse
+	(&SiteVerificationResource{}).Schema(ctx, resource.SchemaRequest{}, &current)
+
+	objectType := current.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		} else {
+			attributes[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return tfsdk.Config{
+		Schema: current.Schema,
+		Raw:    tftypes.NewValue(objectType, attributes),
+	}
+}
+
+func TestDNSDefaultPlanModifiers(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) tftypes.Value { return tftypes.NewValue(tftypes.String, s) }
+
+	tests := map[string]struct {
+		config     map[string]tftypes.Value
+		wantTTL    types.Int64
+		wantRecord types.String
+	}{
+		"defaults": {
+			config:     map[string]tftypes.Value{"site_identifier": str("example.com")},
+			wantTTL:    types.Int64Value(defaultDNSTTL),
+			wantRecord: types.StringValue("example.com."),
+		},
+		"cname": {
+			config:     map[string]tftypes.Value{"site_identifier": str("example.com"), "verification_method": str("DNS_CNAME")},
+			wantTTL:    types.Int64Value(defaultDNSTTL),
+			wantRecord: types.StringNull(),
+		},
+		"file": {
+			config:     map[string]tftypes.Value{"site_identifier": str("https://example.com/"), "site_type": str("SITE"), "verification_method": str("FILE")},
+			wantTTL:    types.Int64Null(),
+			wantRecord: types.StringNull(),
+		},
+		"unknown site identifier": {
+			config:     map[string]tftypes.Value{"site_identifier": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)},
+			wantTTL:    types.Int64Unknown(),
+			wantRecord: types.StringUnknown(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := siteVerificationConfig(t, tt.config)
+			plan := tfsdk.Plan{Schema: config.Schema, Raw: config.Raw}
+
+			ttlReq := planmodifier.Int64Request{
+				Path:        path.Root("dns_ttl"),
+				Config:      config,
+				ConfigValue: types.Int64Null(),
+				Plan:        plan,
+				PlanValue:   types.Int64Unknown(),
+			}
+			ttlResp := &planmodifier.Int64Response{PlanValue: ttlReq.PlanValue}
+			dnsTTLDefault{}.PlanModifyInt64(ctx, ttlReq, ttlResp)
+			if ttlResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", ttlResp.Diagnostics)
+			}
+			if !ttlResp.PlanValue.Equal(tt.wantTTL) {
+				t.Errorf("got dns_ttl %s, want %s", ttlResp.PlanValue, tt.wantTTL)
+			}
+
+			// The prior state value must not be kept once the attribute
+			// is removed from the configuration.
+			recordReq := planmodifier.StringRequest{
+				Path:        path.Root("dns_record_name"),
+				Config:      config,
+				ConfigValue: types.StringNull(),
+				Plan:        plan,
+				PlanValue:   types.StringUnknown(),
+				StateValue:  types.StringValue("_verify.example.com."),
+			}
+			recordResp := &planmodifier.StringResponse{PlanValue: recordReq.PlanValue}
+			dnsRecordNameDefault{}.PlanModifyString(ctx, recordReq, recordResp)
+			if recordResp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", recordResp.Diagnostics)
+			}
+			if !recordResp.PlanValue.Equal(tt.wantRecord) {
+				t.Errorf("got dns_record_name %s, want %s", recordResp.PlanValue, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestDNSDefaultPlanModifiersKeepConfiguredValues(t *testing.T) {
+	ctx := context.Background()
+	config := siteVerificationConfig(t, map[string]tftypes.Value{
+		"site_identifier": tftypes.NewValue(tftypes.String, "example.com"),
+		"dns_ttl":         tftypes.NewValue(tftypes.Number, 300),
+		"dns_record_name": tftypes.NewValue(tftypes.String, "_verify.example.com"),
+	})
+	plan := tfsdk.Plan{Schema: config.Schema, Raw: config.Raw}
+
+	ttlResp := &planmodifier.Int64Response{PlanValue: types.Int64Value(300)}
+	dnsTTLDefault{}.PlanModifyInt64(ctx, planmodifier.Int64Request{
+		Config:      config,
+		ConfigValue: types.Int64Value(300),
+		Plan:        plan,
+		PlanValue:   types.Int64Value(300),
+	}, ttlResp)
+	if !ttlResp.PlanValue.Equal(types.Int64Value(300)) {
+		t.Errorf("got dns_ttl %s, want 300", ttlResp.PlanValue)
+	}
+
+	recordResp := &planmodifier.StringResponse{PlanValue: types.StringValue("_verify.example.com")}
+	dnsRecordNameDefault{}.PlanModifyString(ctx, planmodifier.StringRequest{
+		Config:      config,
+		ConfigValue: types.StringValue("_verify.example.com"),
+		Plan:        plan,
+		PlanValue:   types.StringValue("_verify.example.com"),
+	}, recordResp)
+	if !recordResp.PlanValue.Equal(types.StringValue("_verify.example.com")) {
+		t.Errorf("got dns_record_name %s, want _verify.example.com", recordResp.PlanValue)
+	}
+}
+
+func TestDNSTTLValidator(t *testing.T) {
+	tests := map[string]struct {
+		value   types.Int64
+		wantErr bool
+	}{
+		"positive": {value: types.Int64Value(300)},
+		"null":     {value: types.Int64Null()},
+		"unknown":  {value: types.Int64Unknown()},
+		"zero":     {value: types.Int64Value(0), wantErr: true},
+		"negative": {value: types.Int64Value(-60), wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.Int64Response{}
+			dnsTTLValidator{}.ValidateInt64(context.Background(), validator.Int64Request{
+				Path:        path.Root("dns_ttl"),
+				ConfigValue: tt.value,
+			}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("got error %t, want %t: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	dnsv2 "google.golang.org/api/dns/v2"
+)
+
+const (
+	defaultPropagationTimeout  = 5 * time.Minute
+	defaultPropagationInterval = 10 * time.Second
+)
+
+// newNameserverResolver returns a resolver that sends every query to nameserver.
+func newNameserverResolver(nameserver string) *net.Resolver {
+	addr := net.JoinHostPort(strings.TrimSuffix(nameserver, "."), "53")
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// recordServed reports whether the resolver answers for record with all of its expected values.
+func recordServed(ctx context.Context, resolver *net.Resolver, record *dnsv2.ResourceRecordSet) (bool, error) {
+	switch record.Type {
+	case "TXT":
+		values, err := resolver.LookupTXT(ctx, record.Name)
+		if err != nil {
+			return false, err
+		}
+		for _, want := range record.Rrdatas {
+			if !containsTXTValue(values, want) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case "CNAME":
+		target, err := resolver.LookupCNAME(ctx, record.Name)
+		if err != nil {
+			return false, err
+		}
+		return forceDot(target) == forceDot(record.Rrdatas[0]), nil
+	}
+	return false, fmt.Errorf("unsupported record type %q", record.Type)
+}
+
+// waitForDNSPropagation polls the authoritative nameservers of the managed zone
+// until each of them serves the verification record.
+func (r *SiteVerificationResource) waitForDNSPropagation(ctx context.Context, data *SiteVerificationResourceModel) error {
+	timeout := defaultPropagationTimeout
+	if !data.PropagationTimeout.IsNull() {
+		timeout = time.Duration(data.PropagationTimeout.ValueInt64()) * time.Second
+	}
+	if timeout <= 0 {
+		return nil
+	}
+	interval := defaultPropagationInterval
+	if !data.PropagationInterval.IsNull() && data.PropagationInterval.ValueInt64() > 0 {
+		interval = time.Duration(data.PropagationInterval.ValueInt64()) * time.Second
+	}
+
+	record, err := data.DNSRecordSet()
+	if err != nil {
+		return err
+	}
+	zone, err := r.Clients.DNS.ManagedZones.Get(
+		data.Project.ValueString(),
+		"global",
+		data.ManagedZone.ValueString(),
+	).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to look up nameservers for managed zone: %w", err)
+	}
+	if len(zone.NameServers) == 0 {
+		return fmt.Errorf("managed zone %s has no nameservers", zone.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pending := zone.NameServers
+	for {
+		var remaining []string
+		for _, ns := range pending {
+			served, err := recordServed(ctx, newNameserverResolver(ns), record)
+			tflog.Trace(ctx, "Checked nameserver for verification record", map[string]any{
+				"nameserver": ns,
+				"record":     record.Name,
+				"served":     served,
+				"error":      fmt.Sprint(err),
+			})
+			if !served {
+				remaining = append(remaining, ns)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+		pending = remaining
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for %s record %s on nameservers %s", timeout, record.Type, record.Name, strings.Join(pending, ", "))
+		case <-ticker.C:
+		}
+	}
+}
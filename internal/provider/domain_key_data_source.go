@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	sitev1 "google.golang.org/api/siteverification/v1"
 )
@@ -21,7 +20,8 @@ func NewDomainKeyDataSource() datasource.DataSource {
 
 // DomainKeyDataSource defines the data source implementation.
 type DomainKeyDataSource struct {
-	client *sitev1.Service
+	client   *sitev1.Service
+	identity string
 }
 
 // DomainKeyDataSourceModel describes the data source data model.
@@ -30,6 +30,7 @@ type DomainKeyDataSourceModel struct {
 	SiteIdentifier     types.String `tfsdk:"site_identifier"`
 	SiteType           types.String `tfsdk:"site_type"`
 	Token              types.String `tfsdk:"token"`
+	MetaTag            types.String `tfsdk:"meta_tag"`
 }
 
 func (d *DomainKeyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -60,6 +61,10 @@ func (d *DomainKeyDataSource) Schema(ctx context.Context, req datasource.SchemaR
 				MarkdownDescription: "The verification token to use for the site.",
 				Computed:            true,
 			},
+			"meta_tag": schema.StringAttribute{
+				MarkdownDescription: "The meta element to embed in the head of the site when using the META verification method.",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -82,6 +87,7 @@ func (d *DomainKeyDataSource) Configure(ctx context.Context, req datasource.Conf
 	}
 
 	d.client = data.SiteVerification
+	d.identity = data.SiteVerificationIdentity
 }
 
 func (d *DomainKeyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -102,30 +108,17 @@ func (d *DomainKeyDataSource) Read(ctx context.Context, req datasource.ReadReque
 		data.VerificationMethod = types.StringValue("DNS_TXT")
 	}
 
-	greq := &sitev1.SiteVerificationWebResourceGettokenRequest{
-		Site: &sitev1.SiteVerificationWebResourceGettokenRequestSite{
-			Identifier: data.SiteIdentifier.ValueString(),
-			Type:       data.SiteType.ValueString(),
-		},
-		VerificationMethod: data.VerificationMethod.ValueString(),
-	}
-	tflog.Trace(ctx, "Request", map[string]any{
-		"request": greq,
-	})
-
-	callResp, err := d.client.WebResource.GetToken(greq).Context(ctx).Do()
+	token, err := getVerificationToken(ctx, d.client, data.SiteType.ValueString(), data.SiteIdentifier.ValueString(), data.VerificationMethod.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error retrieving verification token", err.Error())
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving verification token", err, d.identity))
 		return
 	}
 
-	tflog.Trace(ctx, "Response", map[string]any{
-		"status": callResp.ServerResponse.HTTPStatusCode,
-		"header": callResp.ServerResponse.Header,
-		"token":  callResp.Token,
-	})
-
-	data.Token = types.StringValue(callResp.Token)
+	data.Token = types.StringValue(token)
+	data.MetaTag = types.StringNull()
+	if data.SiteType.ValueString() == "SITE" && data.VerificationMethod.ValueString() == "META" {
+		data.MetaTag = types.StringValue(metaTagFromToken(token))
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
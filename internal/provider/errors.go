@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"google.golang.org/api/googleapi"
+)
+
+// apiErrorKind classifies errors returned by the Google APIs.
+type apiErrorKind int
+
+const (
+	apiErrorUnknown apiErrorKind = iota
+	apiErrorNotFound
+	apiErrorPermissionDenied
+	apiErrorQuotaExceeded
+	apiErrorConflict
+	apiErrorVerificationFailed
+	apiErrorUnavailable
+)
+
+func (k apiErrorKind) String() string {
+	switch k {
+	case apiErrorNotFound:
+		return "not found"
+	case apiErrorPermissionDenied:
+		return "permission denied"
+	case apiErrorQuotaExceeded:
+		return "quota exceeded"
+	case apiErrorConflict:
+		return "conflict"
+	case apiErrorVerificationFailed:
+		return "verification failed"
+	case apiErrorUnavailable:
+		return "service unavailable"
+	}
+	return "unknown"
+}
+
+// hint returns advice on how to resolve an error of this kind.
+func (k apiErrorKind) hint() string {
+	switch k {
+	case apiErrorNotFound:
+		return "The requested resource does not exist, or it is not visible to the credentials the provider is using."
+	case apiErrorPermissionDenied:
+		return "The credentials the provider is using lack permission for this operation. Check that the identity has the required IAM roles on the project and is an owner of the site."
+	case apiErrorQuotaExceeded:
+		return "An API quota or rate limit was exceeded. Retry later, reduce parallelism, or request a quota increase."
+	case apiErrorConflict:
+		return "The resource already exists or was modified concurrently. Refresh the state and retry."
+	case apiErrorVerificationFailed:
+		return "Google could not find the verification token. Make sure the record, file or meta tag is published and publicly reachable, then retry once it has propagated."
+	case apiErrorUnavailable:
+		return "The API is temporarily unavailable. Retry later."
+	}
+	return ""
+}
+
+var (
+	notFoundReasons   = []string{"notFound"}
+	permissionReasons = []string{"forbidden", "insufficientPermissions", "accessNotConfigured"}
+	quotaReasons      = []string{"quotaExceeded", "rateLimitExceeded", "userRateLimitExceeded", "dailyLimitExceeded"}
+	conflictReasons   = []string{"conflict", "alreadyExists", "duplicate"}
+)
+
+// classifyAPIError inspects the *googleapi.Error wrapped by err, if any.
+func classifyAPIError(err error) apiErrorKind {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
+		return apiErrorUnknown
+	}
+	switch {
+	case hasReason(gerr, quotaReasons) || gerr.Code == http.StatusTooManyRequests:
+		return apiErrorQuotaExceeded
+	case hasReason(gerr, notFoundReasons) || gerr.Code == http.StatusNotFound:
+		return apiErrorNotFound
+	case hasReason(gerr, permissionReasons) || gerr.Code == http.StatusForbidden || gerr.Code == http.StatusUnauthorized:
+		return apiErrorPermissionDenied
+	case hasReason(gerr, conflictReasons) || gerr.Code == http.StatusConflict || gerr.Code == http.StatusPreconditionFailed:
+		return apiErrorConflict
+	case gerr.Code == http.StatusBadRequest && isVerificationFailure(gerr):
+		return apiErrorVerificationFailed
+	case gerr.Code >= http.StatusInternalServerError:
+		return apiErrorUnavailable
+	}
+	return apiErrorUnknown
+}
+
+func hasReason(gerr *googleapi.Error, reasons []string) bool {
+	for _, item := range gerr.Errors {
+		for _, reason := range reasons {
+			if item.Reason == reason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isVerificationFailure(gerr *googleapi.Error) bool {
+	msg := strings.ToLower(gerr.Message)
+	return strings.Contains(msg, "verification token could not be found") ||
+		strings.Contains(msg, "verification failed")
+}
+
+// isNotFound reports whether err is a Google API not found response.
+func isNotFound(err error) bool {
+	return classifyAPIError(err) == apiErrorNotFound
+}
+
+// apiErrorDiagnostic returns an error diagnostic for err, adding advice on how
+// to resolve it when it is a recognised Google API error. identity names the
+// principal the failing request was made as.
+func apiErrorDiagnostic(summary string, err error, identity string) diag.Diagnostic {
+	kind := classifyAPIError(err)
+	if kind == apiErrorUnknown {
+		return diag.NewErrorDiagnostic(summary, err.Error())
+	}
+	detail := kind.hint()
+	if kind == apiErrorPermissionDenied && identity != "" {
+		detail = fmt.Sprintf("%s Requests were made as %s.", detail, identity)
+	}
+	return diag.NewErrorDiagnostic(
+		fmt.Sprintf("%s: %s", summary, kind),
+		fmt.Sprintf("%s\n\n%s", detail, err.Error()),
+	)
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"golang.org/x/oauth2/google"
+)
+
+const externalAccountType = "external_account"
+
+// externalAccountConfig is the subset of an external account (workload
+// identity federation) credential configuration the provider validates before
+// handing it to the oauth2 library, which only reports problems once the
+// first token is requested.
+type externalAccountConfig struct {
+	Type                           string `json:"type"`
+	Audience                       string `json:"audience"`
+	SubjectTokenType               string `json:"subject_token_type"`
+	TokenURL                       string `json:"token_url"`
+	ServiceAccountImpersonationURL string `json:"service_account_impersonation_url"`
+	CredentialSource               struct {
+		File          string `json:"file"`
+		URL           string `json:"url"`
+		EnvironmentID string `json:"environment_id"`
+		Executable    *struct {
+			Command string `json:"command"`
+		} `json:"executable"`
+		Format struct {
+			Type                  string `json:"type"`
+			SubjectTokenFieldName string `json:"subject_token_field_name"`
+		} `json:"format"`
+	} `json:"credential_source"`
+}
+
+// parseExternalAccount returns the external account configuration in
+// contents, or nil when contents holds another type of credentials. Malformed
+// JSON is left for the oauth2 library to report.
+func parseExternalAccount(contents []byte) *externalAccountConfig {
+	var cfg externalAccountConfig
+	if json.Unmarshal(contents, &cfg) != nil || cfg.Type != externalAccountType {
+		return nil
+	}
+	return &cfg
+}
+
+func (c *externalAccountConfig) validate() error {
+	if c.Audience == "" {
+		return fmt.Errorf("external account credentials are missing an audience")
+	}
+	if c.SubjectTokenType == "" {
+		return fmt.Errorf("external account credentials are missing a subject_token_type")
+	}
+	src := c.CredentialSource
+	switch {
+	case src.File != "":
+		if _, err := os.Stat(src.File); err != nil {
+			return fmt.Errorf("subject token file for external account credentials is not readable: %w", err)
+		}
+	case src.URL != "":
+		if _, err := url.ParseRequestURI(src.URL); err != nil {
+			return fmt.Errorf("subject token URL for external account credentials is invalid: %w", err)
+		}
+	case src.EnvironmentID != "", src.Executable != nil:
+		// Validated by the oauth2 library when the first token is requested.
+	default:
+		return fmt.Errorf("external account credentials must set a file, url, environment_id or executable credential_source")
+	}
+	switch src.Format.Type {
+	case "", "text":
+	case "json":
+		if src.Format.SubjectTokenFieldName == "" {
+			return fmt.Errorf("external account credentials with a json credential_source format must set subject_token_field_name")
+		}
+	default:
+		return fmt.Errorf("unsupported credential_source format %q for external account credentials", src.Format.Type)
+	}
+	return nil
+}
+
+// identity returns the service account impersonated by the configuration, or
+// its workload identity pool audience when it does not impersonate one.
+func (c *externalAccountConfig) identity() string {
+	if c.ServiceAccountImpersonationURL != "" {
+		name := c.ServiceAccountImpersonationURL[strings.LastIndex(c.ServiceAccountImpersonationURL, "/")+1:]
+		return strings.TrimSuffix(name, ":generateAccessToken")
+	}
+	return fmt.Sprintf("external account %s", c.Audience)
+}
+
+// checkExternalAccount validates external account credentials and exchanges
+// a first token, so that misconfigured workload identity federation fails
+// during provider configuration with a clear error. Other credentials are
+// left untouched.
+func checkExternalAccount(ctx context.Context, creds *google.Credentials) error {
+	cfg := parseExternalAccount(creds.JSON)
+	if cfg == nil {
+		return nil
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	tflog.Trace(ctx, "Exchanging external account credentials", map[string]any{
+		"audience":  cfg.Audience,
+		"token_url": cfg.TokenURL,
+	})
+	if _, err := creds.TokenSource.Token(); err != nil {
+		return fmt.Errorf("failed to exchange external account credentials for an access token: %w", err)
+	}
+	return nil
+}
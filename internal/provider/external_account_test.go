@@ -0,0 +1,365 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	credentialspb "cloud.google.com/go/iam/credentials/apiv1/credentialspb"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	grpccredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	testAudience     = "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/provider"
+	testSubjectToken = "subject-token"
+	testSTSToken     = "sts-access-token"
+)
+
+// newFakeSTS starts a security token service that exchanges testSubjectToken
+// for testSTSToken valid for expiresIn seconds, and also serves the subject
+// token for URL-sourced credentials.
+func newFakeSTS(t *testing.T, expiresIn int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got := r.PostForm.Get("subject_token"); got != testSubjectToken {
+			http.Error(w, fmt.Sprintf(`{"error":"invalid_grant","error_description":"unexpected subject token %q"}`, got), http.StatusBadRequest)
+			return
+		}
+		if got := r.PostForm.Get("audience"); got != testAudience {
+			http.Error(w, fmt.Sprintf(`{"error":"invalid_request","error_description":"unexpected audience %q"}`, got), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":      testSTSToken,
+			"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+			"token_type":        "Bearer",
+			"expires_in":        expiresIn,
+		})
+	})
+	mux.HandleFunc("/subject-token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor header", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id_token": testSubjectToken})
+	})
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// redirectTransport sends every request to srv, so that configurations can
+// keep the Google token URLs the oauth2 library insists on.
+type redirectTransport struct {
+	srv *httptest.Server
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "https"
+	req.URL.Host = rt.srv.Listener.Addr().String()
+	return rt.srv.Client().Transport.RoundTrip(req)
+}
+
+// stsContext returns a context whose oauth2 HTTP client talks to srv.
+func stsContext(srv *httptest.Server) context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: redirectTransport{srv}})
+}
+
+func writeSubjectToken(t *testing.T) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(name, []byte(testSubjectToken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func externalAccountJSON(t *testing.T, credentialSource map[string]any) string {
+	t.Helper()
+	cfg := map[string]any{
+		"type":               externalAccountType,
+		"audience":           testAudience,
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":          "https://sts.googleapis.com/v1/token",
+		"credential_source":  credentialSource,
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestExternalAccountValidate(t *testing.T) {
+	tokenFile := writeSubjectToken(t)
+
+	tests := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"file": {
+			config: fmt.Sprintf(`{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"file":%q}}`, tokenFile),
+		},
+		"url with json format": {
+			config: `{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"url":"http://169.254.169.254/token","format":{"type":"json","subject_token_field_name":"id_token"}}}`,
+		},
+		"environment": {
+			config: `{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"environment_id":"aws1"}}`,
+		},
+		"missing audience": {
+			config:  fmt.Sprintf(`{"type":"external_account","subject_token_type":"t","credential_source":{"file":%q}}`, tokenFile),
+			wantErr: "missing an audience",
+		},
+		"missing subject token type": {
+			config:  fmt.Sprintf(`{"type":"external_account","audience":"a","credential_source":{"file":%q}}`, tokenFile),
+			wantErr: "missing a subject_token_type",
+		},
+		"unreadable file": {
+			config:  fmt.Sprintf(`{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"file":%q}}`, filepath.Join(t.TempDir(), "missing")),
+			wantErr: "subject token file",
+		},
+		"invalid url": {
+			config:  `{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"url":"not a url"}}`,
+			wantErr: "subject token URL",
+		},
+		"no credential source": {
+			config:  `{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{}}`,
+			wantErr: "must set a file, url, environment_id or executable",
+		},
+		"json format without field name": {
+			config:  fmt.Sprintf(`{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"file":%q,"format":{"type":"json"}}}`, tokenFile),
+			wantErr: "subject_token_field_name",
+		},
+		"unsupported format": {
+			config:  fmt.Sprintf(`{"type":"external_account","audience":"a","subject_token_type":"t","credential_source":{"file":%q,"format":{"type":"xml"}}}`, tokenFile),
+			wantErr: `unsupported credential_source format "xml"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := parseExternalAccount([]byte(tt.config))
+			if cfg == nil {
+				t.Fatal("expected an external account configuration")
+			}
+			err := cfg.validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %s", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("expected an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("expected an error containing %q, got: %s", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseExternalAccountOtherTypes(t *testing.T) {
+	for _, contents := range []string{
+		`{"type":"service_account"}`,
+		`{"type":"authorized_user"}`,
+		`not json`,
+	} {
+		if cfg := parseExternalAccount([]byte(contents)); cfg != nil {
+			t.Errorf("parseExternalAccount(%s) = %+v, want nil", contents, cfg)
+		}
+	}
+}
+
+func TestExternalAccountCredentials(t *testing.T) {
+	srv := newFakeSTS(t, 3600)
+
+	tests := map[string]string{
+		"file": externalAccountJSON(t, map[string]any{"file": writeSubjectToken(t)}),
+		"url": externalAccountJSON(t, map[string]any{
+			"url":     "http://169.254.169.254/subject-token",
+			"headers": map[string]string{"Metadata-Flavor": "Google"},
+			"format":  map[string]string{"type": "json", "subject_token_field_name": "id_token"},
+		}),
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			creds, diags := jsonCredentials(stsContext(srv), path.Root("credentials"), "credentials", config)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			token, err := creds.TokenSource.Token()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.AccessToken != testSTSToken {
+				t.Errorf("got access token %q, want %q", token.AccessToken, testSTSToken)
+			}
+			if got, want := credentialsIdentity(creds), "external account "+testAudience; got != want {
+				t.Errorf("got identity %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsRefreshAfterConfigure(t *testing.T) {
+	// Tokens expire immediately, so that every use exchanges a new one.
+	srv := newFakeSTS(t, 0)
+
+	ctx, cancel := context.WithCancel(stsContext(srv))
+	data := &GoogleSiteVerificationProviderModel{
+		AccessToken: types.StringNull(),
+		Credentials: types.StringValue(externalAccountJSON(t, map[string]any{"file": writeSubjectToken(t)})),
+	}
+	creds, diags := loadCredentials(ctx, data)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	// The request that configured the provider is done.
+	cancel()
+
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		t.Fatalf("refreshing after configure: %s", err)
+	}
+	if token.AccessToken != testSTSToken {
+		t.Errorf("got access token %q, want %q", token.AccessToken, testSTSToken)
+	}
+}
+
+func TestCheckExternalAccountExchangeFailure(t *testing.T) {
+	srv := newFakeSTS(t, 3600)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("wrong-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := externalAccountJSON(t, map[string]any{"file": tokenFile})
+
+	_, diags := jsonCredentials(stsContext(srv), path.Root("credentials"), "credentials", config)
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if got := diags.Errors()[0].Summary(); got != "Invalid external account credentials" {
+		t.Errorf("got summary %q", got)
+	}
+	if got := diags.Errors()[0].Detail(); !strings.Contains(got, "failed to exchange external account credentials") {
+		t.Errorf("got detail %q", got)
+	}
+}
+
+func TestCheckExternalAccountIgnoresOtherCredentials(t *testing.T) {
+	creds, diags := accessTokenCredentials(path.Root("access_token"), "access_token", "token")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if err := checkExternalAccount(context.Background(), creds); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeIAMCredentials issues access tokens for requests authenticated with
+// testSTSToken, recording the last request.
+type fakeIAMCredentials struct {
+	credentialspb.UnimplementedIAMCredentialsServer
+
+	req           *credentialspb.GenerateAccessTokenRequest
+	authorization string
+}
+
+func (s *fakeIAMCredentials) GenerateAccessToken(ctx context.Context, req *credentialspb.GenerateAccessTokenRequest) (*credentialspb.GenerateAccessTokenResponse, error) {
+	s.req = req
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		s.authorization = md.Get("authorization")[0]
+	}
+	return &credentialspb.GenerateAccessTokenResponse{
+		AccessToken: "impersonated-token",
+		ExpireTime:  timestamppb.New(time.Now().Add(time.Hour)),
+	}, nil
+}
+
+// newFakeIAMCredentials starts a fake IAM Credentials API, served over TLS
+// as the client only sends credentials over secure connections, and returns
+// the options that point a client at it.
+func newFakeIAMCredentials(t *testing.T, iam *fakeIAMCredentials) []option.ClientOption {
+	t.Helper()
+	// Borrow the certificate httptest generates for its TLS servers.
+	certSrv := httptest.NewUnstartedServer(nil)
+	certSrv.StartTLS()
+	cert := certSrv.TLS.Certificates[0]
+	pool := x509.NewCertPool()
+	pool.AddCert(certSrv.Certificate())
+	certSrv.Close()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.Creds(grpccredentials.NewServerTLSFromCert(&cert)))
+	credentialspb.RegisterIAMCredentialsServer(s, iam)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	return []option.ClientOption{
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(grpccredentials.NewClientTLSFromCert(pool, ""))),
+	}
+}
+
+func TestExternalAccountImpersonation(t *testing.T) {
+	srv := newFakeSTS(t, 3600)
+	iam := &fakeIAMCredentials{}
+	opts := newFakeIAMCredentials(t, iam)
+
+	ctx := stsContext(srv)
+	config := externalAccountJSON(t, map[string]any{"file": writeSubjectToken(t)})
+	srcCreds, diags := jsonCredentials(ctx, path.Root("credentials"), "credentials", config)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	creds, err := impersonateServiceAccount(ctx, srcCreds, "target@project.iam.gserviceaccount.com", []string{"delegate@project.iam.gserviceaccount.com"}, 3600, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := iam.authorization, "Bearer "+testSTSToken; got != want {
+		t.Errorf("got authorization %q, want %q", got, want)
+	}
+	if got, want := iam.req.GetName(), "projects/-/serviceAccounts/target@project.iam.gserviceaccount.com"; got != want {
+		t.Errorf("got name %q, want %q", got, want)
+	}
+	if got, want := strings.Join(iam.req.GetDelegates(), ","), "projects/-/serviceAccounts/delegate@project.iam.gserviceaccount.com"; got != want {
+		t.Errorf("got delegates %q, want %q", got, want)
+	}
+
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "impersonated-token" {
+		t.Errorf("got access token %q, want %q", token.AccessToken, "impersonated-token")
+	}
+}
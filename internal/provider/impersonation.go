@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	credentials "cloud.google.com/go/iam/credentials/apiv1"
+	credentialspb "cloud.google.com/go/iam/credentials/apiv1/credentialspb"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	dnsv2 "google.golang.org/api/dns/v2"
+	"google.golang.org/api/option"
+	sitev1 "google.golang.org/api/siteverification/v1"
+	storagev1 "google.golang.org/api/storage/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// clientScopes are the OAuth scopes requested for the provider's API clients.
+var clientScopes = []string{
+	sitev1.SiteverificationScope,
+	sitev1.SiteverificationVerifyOnlyScope,
+	dnsv2.NdevClouddnsReadwriteScope,
+	storagev1.DevstorageReadWriteScope,
+}
+
+// maxRefreshMargin bounds how long before expiry an impersonated token is refreshed.
+const maxRefreshMargin = 5 * time.Minute
+
+// impersonatedTokenSource generates access tokens for a service account using
+// the IAM Credentials API. It is meant to be wrapped in oauth2.ReuseTokenSource,
+// which calls Token again once the previous token is close to expiring.
+type impersonatedTokenSource struct {
+	client    *credentials.IamCredentialsClient
+	name      string
+	delegates []string
+	scopes    []string
+	lifetime  time.Duration
+}
+
+func (ts *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	// The token source outlives the request that configured the provider, so
+	// it cannot use that request's context.
+	ctx := context.Background()
+	req := &credentialspb.GenerateAccessTokenRequest{
+		Name:      ts.name,
+		Delegates: ts.delegates,
+		Scope:     ts.scopes,
+		Lifetime:  durationpb.New(ts.lifetime),
+	}
+	tflog.Trace(ctx, "Request", map[string]any{
+		"request": req,
+	})
+	tokenresp, err := ts.client.GenerateAccessToken(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token for service account: %w", err)
+	}
+	tflog.Trace(ctx, "Response", map[string]any{
+		"expire_time": tokenresp.ExpireTime,
+	})
+	// Report an earlier expiry so the token is replaced before it lapses.
+	margin := ts.lifetime / 4
+	if margin > maxRefreshMargin {
+		margin = maxRefreshMargin
+	}
+	return &oauth2.Token{
+		AccessToken: tokenresp.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      tokenresp.ExpireTime.AsTime().Add(-margin),
+	}, nil
+}
+
+func serviceAccountResourceName(serviceAccount string) string {
+	return fmt.Sprintf("projects/-/serviceAccounts/%s", serviceAccount)
+}
+
+// impersonateServiceAccount returns credentials that act as serviceAccount,
+// optionally through a chain of delegates, refreshing the access token as it
+// nears expiry. opts are passed to the IAM Credentials client.
+func impersonateServiceAccount(ctx context.Context, srcCreds *google.Credentials, serviceAccount string, delegates []string, durationSeconds int64, opts ...option.ClientOption) (*google.Credentials, error) {
+	tflog.Trace(ctx, "Attempting to impersonate service account", map[string]any{
+		"impersonate_service_account": serviceAccount,
+		"delegates":                   delegates,
+	})
+	c, err := credentials.NewIamCredentialsClient(ctx, append(opts, option.WithCredentials(srcCreds))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credentials client: %w", err)
+	}
+	ts := &impersonatedTokenSource{
+		client:   c,
+		name:     serviceAccountResourceName(serviceAccount),
+		scopes:   clientScopes,
+		lifetime: time.Duration(durationSeconds) * time.Second,
+	}
+	for _, delegate := range delegates {
+		ts.delegates = append(ts.delegates, serviceAccountResourceName(delegate))
+	}
+	// Fetch the first token eagerly so configuration errors surface in Configure.
+	token, err := ts.Token()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return &google.Credentials{
+		ProjectID:   srcCreds.ProjectID,
+		TokenSource: oauth2.ReuseTokenSource(token, ts),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	dnsv2 "google.golang.org/api/dns/v2"
+)
+
+// zoneMatchesName reports whether name is the apex of, or a name within, the zone with the given DNS name.
+func zoneMatchesName(zoneDNSName, name string) bool {
+	zoneDNSName = strings.ToLower(forceDot(zoneDNSName))
+	name = strings.ToLower(forceDot(name))
+	return name == zoneDNSName || strings.HasSuffix(name, "."+zoneDNSName)
+}
+
+// discoverManagedZone returns the public managed zone in project with the
+// longest DNS name that contains name.
+func discoverManagedZone(ctx context.Context, client *dnsv2.Service, project, name string) (string, error) {
+	var best *dnsv2.ManagedZone
+	err := client.ManagedZones.List(project, "global").Pages(ctx, func(page *dnsv2.ManagedZonesListResponse) error {
+		for _, zone := range page.ManagedZones {
+			if zone.Visibility != "" && zone.Visibility != "public" {
+				continue
+			}
+			if !zoneMatchesName(zone.DnsName, name) {
+				continue
+			}
+			if best == nil || len(zone.DnsName) > len(best.DnsName) {
+				best = zone
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list managed zones: %w", err)
+	}
+	if best == nil {
+		return "", fmt.Errorf("no public managed zone in project %s contains %s", project, forceDot(name))
+	}
+	tflog.Debug(ctx, "Discovered managed zone", map[string]any{
+		"zone":     best.Name,
+		"dns_name": best.DnsName,
+		"name":     name,
+	})
+	return best.Name, nil
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	metaTagContentRegex = regexp.MustCompile(`content=["']([^"']+)["']`)
+	metaElementRegex    = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	htmlAttributeRegex  = regexp.MustCompile(`(?is)([a-z][a-z0-9-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>/]+))`)
+)
+
+// metaTagContent returns the verification value carried by a META token. The
+// API returns either the complete meta element or just its content value.
+func metaTagContent(token string) string {
+	if m := metaTagContentRegex.FindStringSubmatch(token); m != nil {
+		return m[1]
+	}
+	return strings.TrimSpace(token)
+}
+
+// metaTagFromToken returns the meta element to embed in the head of a site
+// for the given META token.
+func metaTagFromToken(token string) string {
+	return fmt.Sprintf(`<meta name="google-site-verification" content="%s" />`, html.EscapeString(metaTagContent(token)))
+}
+
+// hasVerificationMetaTag reports whether the HTML in body has a
+// google-site-verification meta element whose content is content.
+func hasVerificationMetaTag(body []byte, content string) bool {
+	for _, element := range metaElementRegex.FindAll(body, -1) {
+		attrs := map[string]string{}
+		for _, m := range htmlAttributeRegex.FindAllSubmatch(element, -1) {
+			attrs[strings.ToLower(string(m[1]))] = html.UnescapeString(string(m[2]) + string(m[3]) + string(m[4]))
+		}
+		if strings.EqualFold(attrs["name"], "google-site-verification") && attrs["content"] == content {
+			return true
+		}
+	}
+	return false
+}
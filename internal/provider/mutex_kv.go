@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+)
+
+// mutexKV is a set of mutexes keyed by string, used to serialize operations
+// on a shared remote object across resources managed in parallel.
+type mutexKV struct {
+	lock  sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{
+		store: make(map[string]*sync.Mutex),
+	}
+}
+
+// Lock locks the mutex for key, creating it if needed.
+func (m *mutexKV) Lock(key string) {
+	m.get(key).Lock()
+}
+
+// Unlock unlocks the mutex for key.
+func (m *mutexKV) Unlock(key string) {
+	m.get(key).Unlock()
+}
+
+func (m *mutexKV) get(key string) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+	return mutex
+}
+
+// zoneLockKey returns the key serializing changes to a managed zone.
+func zoneLockKey(project, zone string) string {
+	return fmt.Sprintf("%s/%s", project, zone)
+}
@@ -2,23 +2,21 @@ package provider
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	credentials "cloud.google.com/go/iam/credentials/apiv1"
-	credentialspb "cloud.google.com/go/iam/credentials/apiv1/credentialspb"
-	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	dnsv2 "google.golang.org/api/dns/v2"
 	"google.golang.org/api/option"
 	sitev1 "google.golang.org/api/siteverification/v1"
-	"google.golang.org/protobuf/types/known/durationpb"
+	storagev1 "google.golang.org/api/storage/v1"
 )
 
 // Ensure GoogleSiteVerificationProvider satisfies various provider interfaces.
@@ -35,14 +33,42 @@ type GoogleSiteVerificationProvider struct {
 // GoogleSiteVerificationProviderModel describes the provider data model.
 type GoogleSiteVerificationProviderModel struct {
 	Project                   types.String `tfsdk:"project"`
+	Credentials               types.String `tfsdk:"credentials"`
+	AccessToken               types.String `tfsdk:"access_token"`
 	ImpersonateServiceAccount types.String `tfsdk:"impersonate_service_account"`
+	ImpersonateDelegates      types.List   `tfsdk:"impersonate_service_account_delegates"`
+	DNSImpersonateAccount     types.String `tfsdk:"dns_impersonate_service_account"`
+	StorageImpersonateAccount types.String `tfsdk:"storage_impersonate_service_account"`
 	TokenDuration             types.Int64  `tfsdk:"token_duration"`
+
+	SiteVerificationCustomEndpoint types.String `tfsdk:"site_verification_custom_endpoint"`
+	DNSCustomEndpoint              types.String `tfsdk:"dns_custom_endpoint"`
+	StorageCustomEndpoint          types.String `tfsdk:"storage_custom_endpoint"`
+	IAMCredentialsCustomEndpoint   types.String `tfsdk:"iam_credentials_custom_endpoint"`
+
+	MaxRetries        types.Int64   `tfsdk:"max_retries"`
+	RequestTimeout    types.String  `tfsdk:"request_timeout"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
 }
 
+// SiteVerificationClients holds the API clients shared by resources and data
+// sources, along with the identity each client authenticates as.
 type SiteVerificationClients struct {
 	ProjectID        string
 	SiteVerification *sitev1.Service
 	DNS              *dnsv2.Service
+	Storage          *storagev1.Service
+
+	SiteVerificationIdentity string
+	DNSIdentity              string
+	StorageIdentity          string
+
+	// ZoneLocks serializes changes to each managed zone, keyed by zoneLockKey,
+	// as Cloud DNS rejects concurrent changes to the same zone.
+	ZoneLocks *mutexKV
+
+	// SiteLocks serializes changes to the owners of each site, keyed by site ID.
+	SiteLocks *mutexKV
 }
 
 func (p *GoogleSiteVerificationProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -57,16 +83,67 @@ func (p *GoogleSiteVerificationProvider) Schema(ctx context.Context, req provide
 				MarkdownDescription: "The project ID to manage resources in. If it is not provided, the default project is used.",
 				Optional:            true,
 			},
+			"credentials": schema.StringAttribute{
+				MarkdownDescription: "Either the path to or the contents of a credentials JSON file. Service account keys, authorized user credentials and `external_account` workload identity federation configurations are supported. Conflicts with `access_token`. Can also be set with the `GOOGLE_CREDENTIALS` environment variable. If neither this nor `access_token` is set, application default credentials are used.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "A temporary OAuth 2.0 access token to authenticate with. Conflicts with `credentials`. Can also be set with the `GOOGLE_OAUTH_ACCESS_TOKEN` environment variable, which takes precedence over `GOOGLE_CREDENTIALS`. `project` should be set when using an access token, as it cannot be inferred.",
+				Optional:            true,
+				Sensitive:           true,
+			},
 			"impersonate_service_account": schema.StringAttribute{
 				MarkdownDescription: "The service account ID to impersonate, if any. For more information on service account impersonation, see [the official documentation](https://cloud.google.com/iam/docs/impersonating-service-accounts).",
 				Optional:            true,
 				Required:            false,
 			},
+			"impersonate_service_account_delegates": schema.ListAttribute{
+				MarkdownDescription: "The delegation chain of service accounts to traverse when impersonating `impersonate_service_account`. Each service account must be granted `roles/iam.serviceAccountTokenCreator` on the next one in the chain.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"dns_impersonate_service_account": schema.StringAttribute{
+				MarkdownDescription: "The service account ID to impersonate for Cloud DNS requests. Impersonated directly by the provider credentials, without `impersonate_service_account_delegates`. Defaults to `impersonate_service_account`.",
+				Optional:            true,
+			},
+			"storage_impersonate_service_account": schema.StringAttribute{
+				MarkdownDescription: "The service account ID to impersonate for Cloud Storage requests. Impersonated directly by the provider credentials, without `impersonate_service_account_delegates`. Defaults to `impersonate_service_account`.",
+				Optional:            true,
+			},
 			"token_duration": schema.Int64Attribute{
-				MarkdownDescription: "The duration of the token to impersonate the service account. If not set, the default duration of 1 hour will be used.",
+				MarkdownDescription: "The lifetime, in seconds, of each token generated for the impersonated service account. Tokens are refreshed before they expire. If not set, the default duration of 1 hour will be used.",
 				Optional:            true,
 				Required:            false,
 			},
+			"site_verification_custom_endpoint": schema.StringAttribute{
+				MarkdownDescription: "A custom endpoint for the Site Verification API, such as `https://www.googleapis.com/siteVerification/v1/`.",
+				Optional:            true,
+			},
+			"dns_custom_endpoint": schema.StringAttribute{
+				MarkdownDescription: "A custom endpoint for the Cloud DNS API, such as `https://dns.googleapis.com/dns/v2/`.",
+				Optional:            true,
+			},
+			"storage_custom_endpoint": schema.StringAttribute{
+				MarkdownDescription: "A custom endpoint for the Cloud Storage API, such as `https://storage.googleapis.com/storage/v1/`.",
+				Optional:            true,
+			},
+			"iam_credentials_custom_endpoint": schema.StringAttribute{
+				MarkdownDescription: "A custom gRPC endpoint for the IAM Credentials API used for service account impersonation, such as `iamcredentials.googleapis.com:443`.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "How many times to retry Google API requests that fail with a 429 or 5xx response. Only idempotent requests are retried on 5xx responses. Defaults to 5.",
+				Optional:            true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "The timeout for each attempt of a Google API request, as a duration such as `30s` or `2m`. Defaults to `2m`.",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "The maximum number of requests per second to send to each Google API. Unlimited when not set.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -80,48 +157,120 @@ func (p *GoogleSiteVerificationProvider) Configure(ctx context.Context, req prov
 		return
 	}
 
-	tflog.Trace(ctx, "Attempting to load default credentials")
-	defaultCreds, err := google.FindDefaultCredentials(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to load default credentials", err.Error())
+	data.Credentials = credentialsValue(data.Credentials)
+	data.AccessToken = credentialsValue(data.AccessToken)
+	defaultCreds, diags := loadCredentials(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	creds := defaultCreds
-	if !data.ImpersonateServiceAccount.IsNull() {
-		duration := int64(3600)
-		if !data.TokenDuration.IsNull() {
-			duration = data.TokenDuration.ValueInt64()
+
+	var err error
+	if !data.Project.IsNull() {
+		defaultCreds.ProjectID = data.Project.ValueString()
+	}
+	if defaultCreds.ProjectID == "" {
+		resp.Diagnostics.AddAttributeWarning(path.Root("project"), "Project not set", "The project could not be inferred from the credentials. Set project, or the project attribute of each resource, to manage Cloud DNS records.")
+	}
+
+	transport := transportConfig{
+		maxRetries:     defaultMaxRetries,
+		requestTimeout: defaultRequestTimeout,
+	}
+	if !data.MaxRetries.IsNull() {
+		if data.MaxRetries.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("max_retries"), "Invalid max_retries", "max_retries must not be negative.")
+			return
 		}
-		creds, err = impersonateServiceAccount(ctx, creds, data.ImpersonateServiceAccount.ValueString(), duration)
+		transport.maxRetries = int(data.MaxRetries.ValueInt64())
 	}
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to build credentials", err.Error())
+	if !data.RequestTimeout.IsNull() {
+		transport.requestTimeout, err = time.ParseDuration(data.RequestTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("request_timeout"), "Invalid request_timeout", err.Error())
+			return
+		}
+	}
+	if !data.RequestsPerSecond.IsNull() {
+		transport.requestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	}
+
+	duration := int64(3600)
+	if !data.TokenDuration.IsNull() {
+		duration = data.TokenDuration.ValueInt64()
+	}
+	var delegates []string
+	if !data.ImpersonateDelegates.IsNull() {
+		delegates, err = listValueToStringSlice(ctx, data.ImpersonateDelegates)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("impersonate_service_account_delegates"), "Failed to read delegates", err.Error())
+			return
+		}
+	}
+
+	// Each client impersonates its own service account when one is configured,
+	// and otherwise falls back to the provider-wide impersonation settings. The
+	// delegation chain belongs to impersonate_service_account alone, so the
+	// per-API accounts are impersonated directly.
+	impersonate := func(attr string, serviceAccount types.String, delegates []string, fallback *google.Credentials, fallbackIdentity string) (*google.Credentials, string, bool) {
+		if serviceAccount.IsNull() {
+			return fallback, fallbackIdentity, true
+		}
+		creds, err := impersonateServiceAccount(ctx, defaultCreds, serviceAccount.ValueString(), delegates, duration, endpointOptions(data.IAMCredentialsCustomEndpoint)...)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(attr), "Failed to build credentials", err.Error())
+			return nil, "", false
+		}
+		return creds, serviceAccount.ValueString(), true
+	}
+	creds, identity, ok := impersonate("impersonate_service_account", data.ImpersonateServiceAccount, delegates, defaultCreds, credentialsIdentity(defaultCreds))
+	if !ok {
 		return
 	}
-	if !data.Project.IsNull() {
-		defaultCreds.ProjectID = data.Project.String()
-		creds.ProjectID = data.Project.String()
+	dnsCreds, dnsIdentity, ok := impersonate("dns_impersonate_service_account", data.DNSImpersonateAccount, nil, creds, identity)
+	if !ok {
+		return
+	}
+	storageCreds, storageIdentity, ok := impersonate("storage_impersonate_service_account", data.StorageImpersonateAccount, nil, creds, identity)
+	if !ok {
+		return
 	}
+	tflog.Debug(ctx, "Configured credentials", map[string]any{
+		"site_verification_identity": identity,
+		"dns_identity":               dnsIdentity,
+		"storage_identity":           storageIdentity,
+	})
 
-	siteverificationService, err := sitev1.NewService(ctx,
-		option.WithCredentials(creds),
-		option.WithScopes(sitev1.SiteverificationScope, sitev1.SiteverificationVerifyOnlyScope))
+	siteverificationService, err := sitev1.NewService(ctx, append(endpointOptions(data.SiteVerificationCustomEndpoint),
+		option.WithHTTPClient(newHTTPClient(creds, transport)))...)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create siteverification client", err.Error())
 		return
 	}
-	dnsservice, err := dnsv2.NewService(ctx,
-		option.WithCredentials(defaultCreds),
-		option.WithScopes(dnsv2.NdevClouddnsReadwriteScope))
+	dnsservice, err := dnsv2.NewService(ctx, append(endpointOptions(data.DNSCustomEndpoint),
+		option.WithHTTPClient(newHTTPClient(dnsCreds, transport)))...)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create dns client", err.Error())
 		return
 	}
+	storageservice, err := storagev1.NewService(ctx, append(endpointOptions(data.StorageCustomEndpoint),
+		option.WithHTTPClient(newHTTPClient(storageCreds, transport)))...)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create storage client", err.Error())
+		return
+	}
 
 	clients := &SiteVerificationClients{
-		ProjectID:        creds.ProjectID,
-		SiteVerification: siteverificationService,
-		DNS:              dnsservice,
+		ProjectID:                defaultCreds.ProjectID,
+		SiteVerification:         siteverificationService,
+		DNS:                      dnsservice,
+		Storage:                  storageservice,
+		SiteVerificationIdentity: identity,
+		DNSIdentity:              dnsIdentity,
+		StorageIdentity:          storageIdentity,
+		ZoneLocks:                newMutexKV(),
+		SiteLocks:                newMutexKV(),
 	}
 
 	resp.DataSourceData = clients
@@ -131,15 +280,27 @@ func (p *GoogleSiteVerificationProvider) Configure(ctx context.Context, req prov
 func (p *GoogleSiteVerificationProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewSiteVerificationResource,
+		NewSiteOwnerResource,
+		NewSiteOwnersResource,
 	}
 }
 
 func (p *GoogleSiteVerificationProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewDomainKeyDataSource,
+		NewSiteDataSource,
+		NewSitesDataSource,
 	}
 }
 
+// endpointOptions returns the client options overriding an API endpoint, if one is configured.
+func endpointOptions(endpoint types.String) []option.ClientOption {
+	if endpoint.IsNull() || endpoint.ValueString() == "" {
+		return nil
+	}
+	return []option.ClientOption{option.WithEndpoint(endpoint.ValueString())}
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &GoogleSiteVerificationProvider{
@@ -147,41 +308,3 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
-
-func impersonateServiceAccount(ctx context.Context, srcCreds *google.Credentials, serviceAccount string, durationSeconds int64) (*google.Credentials, error) {
-	tflog.Trace(ctx, "Attempting to impersonate service account", map[string]any{
-		"impersonate_service_account": serviceAccount,
-	})
-	c, err := credentials.NewIamCredentialsClient(ctx, option.WithCredentials(srcCreds))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create credentials client: %w", err)
-	}
-	defer c.Close()
-	req := &credentialspb.GenerateAccessTokenRequest{
-		Name: fmt.Sprintf("projects/-/serviceAccounts/%s", serviceAccount),
-		Scope: []string{
-			sitev1.SiteverificationScope,
-			sitev1.SiteverificationVerifyOnlyScope,
-			dnsv2.NdevClouddnsReadwriteScope,
-		},
-		Lifetime: &durationpb.Duration{
-			Seconds: durationSeconds,
-		},
-	}
-	tflog.Trace(ctx, "Request", map[string]any{
-		"request": req,
-	})
-	tokenresp, err := c.GenerateAccessToken(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token for service account: %w", err)
-	}
-	tflog.Trace(ctx, "Response", map[string]any{
-		"expire_time": tokenresp.ExpireTime,
-		"token":       tokenresp.AccessToken,
-	})
-	return &google.Credentials{
-		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{
-			AccessToken: tokenresp.AccessToken,
-		}),
-	}, nil
-}
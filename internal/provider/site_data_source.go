@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	sitev1 "google.golang.org/api/siteverification/v1"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SiteDataSource{}
+
+func NewSiteDataSource() datasource.DataSource {
+	return &SiteDataSource{}
+}
+
+// SiteDataSource defines the data source implementation.
+type SiteDataSource struct {
+	client   *sitev1.Service
+	identity string
+}
+
+// SiteDataSourceModel describes the data source data model.
+type SiteDataSourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	SiteIdentifier types.String `tfsdk:"site_identifier"`
+	SiteType       types.String `tfsdk:"site_type"`
+	Owners         types.List   `tfsdk:"owners"`
+	Verified       types.Bool   `tfsdk:"verified"`
+}
+
+// webResourceID returns the ID the Site Verification API gives a site.
+func webResourceID(siteType, identifier string) string {
+	if siteType == "INET_DOMAIN" {
+		return fmt.Sprintf("dns://%s", strings.TrimSuffix(identifier, "."))
+	}
+	return identifier
+}
+
+func (d *SiteDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_site"
+}
+
+func (d *SiteDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Looks up a verified site, including sites verified outside Terraform.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the site. Exactly one of `id` and `site_identifier` must be set.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"site_identifier": schema.StringAttribute{
+				MarkdownDescription: "The DNS name or URL of the site. Exactly one of `id` and `site_identifier` must be set.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"site_type": schema.StringAttribute{
+				MarkdownDescription: "The type of the site, used with `site_identifier`. Defaults to INET_DOMAIN.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"owners": schema.ListAttribute{
+				MarkdownDescription: "The owners of the site.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"verified": schema.BoolAttribute{
+				MarkdownDescription: "Whether the site is verified for the credentials the provider is using.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SiteDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*SiteVerificationClients)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SiteVerificationClients, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.SiteVerification
+	d.identity = data.SiteVerificationIdentity
+}
+
+func (d *SiteDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SiteDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ID.IsNull() == data.SiteIdentifier.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid site lookup",
+			"Exactly one of id and site_identifier must be set.",
+		)
+		return
+	}
+
+	id := data.ID.ValueString()
+	if data.ID.IsNull() {
+		if data.SiteType.IsNull() {
+			data.SiteType = types.StringValue("INET_DOMAIN")
+		}
+		id = webResourceID(data.SiteType.ValueString(), data.SiteIdentifier.ValueString())
+	}
+
+	tflog.Trace(ctx, "Looking up site verification", map[string]any{
+		"id": id,
+	})
+	callResp, err := d.client.WebResource.Get(id).Context(ctx).Do()
+	if err != nil {
+		switch classifyAPIError(err) {
+		case apiErrorNotFound:
+			// The API reports sites the caller has not verified as missing.
+			data.ID = types.StringValue(id)
+			data.Owners = types.ListNull(types.StringType)
+			data.Verified = types.BoolValue(false)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		case apiErrorPermissionDenied:
+			resp.Diagnostics.AddError(
+				"Access denied to site",
+				fmt.Sprintf("%s is not an owner of %s, so its verification cannot be read. Add it as an owner of the site, or configure the provider with credentials that are.", d.identity, id),
+			)
+		default:
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error reading site verification", err, d.identity))
+		}
+		return
+	}
+
+	tflog.Trace(ctx, "Response", map[string]any{
+		"status": callResp.ServerResponse.HTTPStatusCode,
+		"owners": callResp.Owners,
+	})
+
+	// Keep configured values as written, only filling in the others.
+	if data.ID.IsNull() {
+		decoded, err := decodeID(callResp.Id)
+		if err != nil {
+			resp.Diagnostics.AddError("Error decoding site ID", err.Error())
+			return
+		}
+		data.ID = types.StringValue(decoded)
+	}
+	if callResp.Site != nil {
+		if data.SiteIdentifier.IsNull() {
+			data.SiteIdentifier = types.StringValue(callResp.Site.Identifier)
+		}
+		if data.SiteType.IsNull() {
+			data.SiteType = types.StringValue(callResp.Site.Type)
+		}
+	}
+	owners, diags := parseOwnersFromResponse(callResp)
+	resp.Diagnostics.Append(diags...)
+	data.Owners = owners
+	data.Verified = types.BoolValue(true)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SiteOwnerResource{}
+var _ resource.ResourceWithImportState = &SiteOwnerResource{}
+
+func NewSiteOwnerResource() resource.Resource {
+	return &SiteOwnerResource{}
+}
+
+// SiteOwnerResource defines the resource implementation.
+type SiteOwnerResource struct {
+	Clients *SiteVerificationClients
+}
+
+// SiteOwnerResourceModel describes the resource data model.
+type SiteOwnerResourceModel struct {
+	SiteID types.String `tfsdk:"site_id"`
+	Owner  types.String `tfsdk:"owner"`
+	ID     types.String `tfsdk:"id"`
+}
+
+func (r *SiteOwnerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_site_owner"
+}
+
+func (r *SiteOwnerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Adds an owner to a verified site, leaving its other owners untouched. On destroy only this owner is removed.",
+
+		Attributes: map[string]schema.Attribute{
+			"site_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the verified site.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "The email address of the owner to add.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the site owner, in the form `<site_id>/<owner>`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SiteOwnerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*SiteVerificationClients)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SiteVerificationClients, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Clients = data
+}
+
+func (r *SiteOwnerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SiteOwnerResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	owner := data.Owner.ValueString()
+	_, err := updateSiteOwners(ctx, r.Clients, data.SiteID.ValueString(), func(owners []string) ([]string, bool) {
+		if containsOwner(owners, owner) {
+			return owners, false
+		}
+		return append(owners, owner), true
+	})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error adding site owner", err, r.Clients.SiteVerificationIdentity))
+		return
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.SiteID.ValueString(), owner))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SiteOwnerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SiteOwnerResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	callResp, err := r.Clients.SiteVerification.WebResource.Get(data.SiteID.ValueString()).Context(ctx).Do()
+	if err != nil {
+		if isNotFound(err) {
+			tflog.Trace(ctx, "Site verification not found", map[string]any{"id": data.ID.String()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading site owners", err, r.Clients.SiteVerificationIdentity))
+		return
+	}
+
+	if !containsOwner(callResp.Owners, data.Owner.ValueString()) {
+		tflog.Trace(ctx, "Site owner not found", map[string]any{"id": data.ID.String()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SiteOwnerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SiteOwnerResourceModel
+
+	// Every attribute requires replacement, so there is nothing to update.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SiteOwnerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SiteOwnerResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	owner := data.Owner.ValueString()
+	_, err := updateSiteOwners(ctx, r.Clients, data.SiteID.ValueString(), func(owners []string) ([]string, bool) {
+		if !containsOwner(owners, owner) {
+			return owners, false
+		}
+		return removeOwners(owners, []string{owner}), true
+	})
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error removing site owner", err, r.Clients.SiteVerificationIdentity))
+	}
+}
+
+func (r *SiteOwnerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	siteID, owner, ok := splitOwnerID(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected an ID of the form <site_id>/<owner>, got %q.", req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("site_id"), siteID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("owner"), owner)...)
+}
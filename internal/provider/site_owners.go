@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	sitev1 "google.golang.org/api/siteverification/v1"
+)
+
+const (
+	ownerUpdateRetries    = 5
+	ownerUpdateBackoff    = 2 * time.Second
+	ownerUpdateMaxBackoff = 30 * time.Second
+)
+
+// updateSiteOwners applies fn to the current owners of the site with the given
+// id and saves the result, returning the owners the site is left with. fn
+// returns the new owners and whether they differ from the current ones. The
+// API offers no preconditions, so a conflicting concurrent update is retried
+// from a fresh read.
+func updateSiteOwners(ctx context.Context, clients *SiteVerificationClients, id string, fn func(owners []string) ([]string, bool)) ([]string, error) {
+	clients.SiteLocks.Lock(id)
+	defer clients.SiteLocks.Unlock(id)
+
+	backoff := ownerUpdateBackoff
+	for attempt := 0; ; attempt++ {
+		owners, err := updateSiteOwnersOnce(ctx, clients.SiteVerification, id, fn)
+		if err == nil || attempt >= ownerUpdateRetries || classifyAPIError(err) != apiErrorConflict {
+			return owners, err
+		}
+		tflog.Debug(ctx, "Updating site owners conflicted, retrying", map[string]any{
+			"id":      id,
+			"attempt": attempt + 1,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > ownerUpdateMaxBackoff {
+			backoff = ownerUpdateMaxBackoff
+		}
+	}
+}
+
+func updateSiteOwnersOnce(ctx context.Context, client *sitev1.Service, id string, fn func(owners []string) ([]string, bool)) ([]string, error) {
+	current, err := client.WebResource.Get(id).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	owners, changed := fn(current.Owners)
+	if !changed {
+		return current.Owners, nil
+	}
+	tflog.Trace(ctx, "Patching site owners", map[string]any{
+		"id":     id,
+		"before": current.Owners,
+		"after":  owners,
+	})
+	callResp, err := client.WebResource.Patch(id, &sitev1.SiteVerificationWebResourceResource{
+		Owners: owners,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return callResp.Owners, nil
+}
+
+// removeOwners returns owners without any of those in remove, ignoring case.
+func removeOwners(owners, remove []string) []string {
+	var kept []string
+	for _, owner := range owners {
+		if !containsOwner(remove, owner) {
+			kept = append(kept, owner)
+		}
+	}
+	return kept
+}
+
+// splitOwnerID splits a site owner ID of the form <site id>/<owner>. Site IDs
+// contain slashes of their own, so the owner is taken from the last one.
+func splitOwnerID(id string) (string, string, bool) {
+	i := strings.LastIndex(id, "/")
+	if i <= 0 || i == len(id)-1 {
+		return "", "", false
+	}
+	return id[:i], id[i+1:], true
+}
+
+// preserveOwnerSpelling returns owners with each one that matches a known
+// owner, ignoring case, spelled as the known one.
+func preserveOwnerSpelling(owners, known []string) []string {
+	preserved := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		for _, k := range known {
+			if strings.EqualFold(k, owner) {
+				owner = k
+				break
+			}
+		}
+		preserved = append(preserved, owner)
+	}
+	return preserved
+}
+
+// sameOwners reports whether a and b hold the same owners, ignoring order and case.
+func sameOwners(a, b []string) bool {
+	for _, owner := range a {
+		if !containsOwner(b, owner) {
+			return false
+		}
+	}
+	for _, owner := range b {
+		if !containsOwner(a, owner) {
+			return false
+		}
+	}
+	return true
+}
+
+// identityEmail returns the email address in a credentials identity, if the
+// identity is one. Identities of user or token credentials cannot be
+// determined locally and are only descriptions.
+func identityEmail(identity string) (string, bool) {
+	if !strings.Contains(identity, "@") || strings.ContainsAny(identity, " ") {
+		return "", false
+	}
+	return identity, true
+}
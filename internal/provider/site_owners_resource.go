@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SiteOwnersResource{}
+var _ resource.ResourceWithImportState = &SiteOwnersResource{}
+var _ resource.ResourceWithModifyPlan = &SiteOwnersResource{}
+
+func NewSiteOwnersResource() resource.Resource {
+	return &SiteOwnersResource{}
+}
+
+// SiteOwnersResource defines the resource implementation.
+type SiteOwnersResource struct {
+	Clients *SiteVerificationClients
+}
+
+// SiteOwnersResourceModel describes the resource data model.
+type SiteOwnersResourceModel struct {
+	SiteID          types.String `tfsdk:"site_id"`
+	Owners          types.Set    `tfsdk:"owners"`
+	AllowRemoveSelf types.Bool   `tfsdk:"allow_remove_self"`
+	ID              types.String `tfsdk:"id"`
+}
+
+func (r *SiteOwnersResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_site_owners"
+}
+
+func (r *SiteOwnersResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Manages the complete list of owners of a verified site. Owners added outside Terraform are removed on the next apply. Destroying the resource leaves the owners unchanged.",
+
+		Attributes: map[string]schema.Attribute{
+			"site_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the verified site.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"owners": schema.SetAttribute{
+				MarkdownDescription: "The email addresses of the owners of the site. Compared case-insensitively.",
+				Required:            true,
+				ElementType:         types.StringType,
+			},
+			"allow_remove_self": schema.BoolAttribute{
+				MarkdownDescription: "Whether `owners` may leave out the identity the provider is using, which would stop the provider from managing the site. When the email address of that identity is not known, for example with user or external account credentials that do not impersonate a service account, the check is skipped with a warning unless this is set. Defaults to false.",
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the site.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SiteOwnersResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*SiteVerificationClients)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SiteVerificationClients, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Clients = data
+}
+
+// ModifyPlan refuses plans that would remove the provider's own identity from
+// the owners, so that the mistake is caught before anything is applied.
+func (r *SiteOwnersResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy, or before the provider has been configured.
+	if req.Plan.Raw.IsNull() || r.Clients == nil {
+		return
+	}
+
+	var data *SiteOwnersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Owners.IsUnknown() || data.AllowRemoveSelf.IsUnknown() {
+		return
+	}
+
+	r.checkSelfRemoval(ctx, &resp.Diagnostics, data)
+}
+
+func (r *SiteOwnersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SiteOwnersResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setOwners(ctx, &resp.Diagnostics, data)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.SiteID
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SiteOwnersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SiteOwnersResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	callResp, err := r.Clients.SiteVerification.WebResource.Get(data.SiteID.ValueString()).Context(ctx).Do()
+	if err != nil {
+		if isNotFound(err) {
+			tflog.Trace(ctx, "Site verification not found", map[string]any{"id": data.ID.String()})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading site owners", err, r.Clients.SiteVerificationIdentity))
+		return
+	}
+
+	var known []string
+	if !data.Owners.IsNull() {
+		resp.Diagnostics.Append(data.Owners.ElementsAs(ctx, &known, false)...)
+	}
+
+	// Keep the spelling of owners already in state, so that the API
+	// normalizing the case of an email address does not produce a diff.
+	owners := preserveOwnerSpelling(callResp.Owners, known)
+	tflog.Trace(ctx, "Read site owners", map[string]any{
+		"id":     data.ID.String(),
+		"owners": owners,
+	})
+	ownersValue, diags := types.SetValueFrom(ctx, types.StringType, owners)
+	resp.Diagnostics.Append(diags...)
+	data.Owners = ownersValue
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SiteOwnersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SiteOwnersResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.setOwners(ctx, &resp.Diagnostics, data)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SiteOwnersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Removing every owner is not possible, and removing all but the provider
+	// would lock out the other owners, so the owners are left as they are.
+	tflog.Trace(ctx, "Leaving site owners unchanged on destroy")
+}
+
+func (r *SiteOwnersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("site_id"), req.ID)...)
+}
+
+// checkSelfRemoval adds an error when the owners of data leave out the
+// identity the provider is using and allow_remove_self is not set, or a
+// warning when that identity has no known email address to look for.
+func (r *SiteOwnersResource) checkSelfRemoval(ctx context.Context, diags *diag.Diagnostics, data *SiteOwnersResourceModel) {
+	if data.AllowRemoveSelf.ValueBool() {
+		return
+	}
+	self, ok := identityEmail(r.Clients.SiteVerificationIdentity)
+	if !ok {
+		diags.AddAttributeWarning(
+			path.Root("owners"),
+			"Unable to check owners for the provider's own identity",
+			fmt.Sprintf("The provider is using %s, whose email address is not known, so it cannot check that owners still includes it. Make sure owners includes the account the provider authenticates as, and set allow_remove_self to silence this warning.", r.Clients.SiteVerificationIdentity),
+		)
+		return
+	}
+	var owners []string
+	diags.Append(data.Owners.ElementsAs(ctx, &owners, false)...)
+	if diags.HasError() {
+		return
+	}
+	if !containsOwner(owners, self) {
+		diags.AddAttributeError(
+			path.Root("owners"),
+			"Refusing to remove the provider's own identity",
+			fmt.Sprintf("The provider is using %s, which would no longer be able to manage the site. Add it to owners, or set allow_remove_self to remove it anyway.", self),
+		)
+	}
+}
+
+// setOwners replaces the owners of the site with those of data.
+func (r *SiteOwnersResource) setOwners(ctx context.Context, diags *diag.Diagnostics, data *SiteOwnersResourceModel) {
+	r.checkSelfRemoval(ctx, diags, data)
+
+	if diags.HasError() {
+		return
+	}
+
+	var desired []string
+	diags.Append(data.Owners.ElementsAs(ctx, &desired, false)...)
+
+	if diags.HasError() {
+		return
+	}
+
+	_, err := updateSiteOwners(ctx, r.Clients, data.SiteID.ValueString(), func(owners []string) ([]string, bool) {
+		return desired, !sameOwners(owners, desired)
+	})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Error updating site owners", err, r.Clients.SiteVerificationIdentity))
+	}
+}
@@ -5,14 +5,18 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -23,6 +27,16 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &SiteVerificationResource{}
 var _ resource.ResourceWithImportState = &SiteVerificationResource{}
+var _ resource.ResourceWithModifyPlan = &SiteVerificationResource{}
+var _ resource.ResourceWithUpgradeState = &SiteVerificationResource{}
+
+const (
+	defaultCreateTimeout = 20 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 20 * time.Minute
+	defaultDeleteTimeout = 10 * time.Minute
+	cleanupTimeout       = 2 * time.Minute
+)
 
 func NewSiteVerificationResource() resource.Resource {
 	return &SiteVerificationResource{}
@@ -35,22 +49,122 @@ type SiteVerificationResource struct {
 
 // SiteVerificationResourceModel describes the resource data model.
 type SiteVerificationResourceModel struct {
-	Project            types.String `tfsdk:"project"`
-	VerificationMethod types.String `tfsdk:"verification_method"`
-	SiteIdentifier     types.String `tfsdk:"site_identifier"`
-	SiteType           types.String `tfsdk:"site_type"`
-	Token              types.String `tfsdk:"token"`
-	ManagedZone        types.String `tfsdk:"managed_zone"`
-	Owners             types.List   `tfsdk:"owners"`
-	ID                 types.String `tfsdk:"id"`
+	Project             types.String   `tfsdk:"project"`
+	VerificationMethod  types.String   `tfsdk:"verification_method"`
+	SiteIdentifier      types.String   `tfsdk:"site_identifier"`
+	SiteType            types.String   `tfsdk:"site_type"`
+	Token               types.String   `tfsdk:"token"`
+	ManagedZone         types.String   `tfsdk:"managed_zone"`
+	DNSTTL              types.Int64    `tfsdk:"dns_ttl"`
+	DNSRecordName       types.String   `tfsdk:"dns_record_name"`
+	Owners              types.Set      `tfsdk:"owners"`
+	WebRoot             types.Object   `tfsdk:"web_root"`
+	PropagationTimeout  types.Int64    `tfsdk:"propagation_timeout"`
+	PropagationInterval types.Int64    `tfsdk:"propagation_interval"`
+	VerificationRetries types.Int64    `tfsdk:"verification_retries"`
+	MetaTag             types.String   `tfsdk:"meta_tag"`
+	VerifyMetaTag       types.Bool     `tfsdk:"verify_meta_tag"`
+	ID                  types.String   `tfsdk:"id"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (s *SiteVerificationResourceModel) EncodedID() string {
 	return url.PathEscape(s.ID.ValueString())
 }
 
-func (s *SiteVerificationResourceModel) SiteID() string {
-	return strings.TrimSuffix(s.SiteIdentifier.ValueString(), ".")
+// WebResourceID returns the ID the API gives the site, used for every call
+// on it. It is also the site_id of the owner resources, so that owner updates
+// share their lock.
+func (s *SiteVerificationResourceModel) WebResourceID() string {
+	return webResourceID(s.SiteType.ValueString(), s.SiteIdentifier.ValueString())
+}
+
+// UsesDNS reports whether the site is verified through a record in a Cloud DNS managed zone.
+func (s *SiteVerificationResourceModel) UsesDNS() bool {
+	if s.SiteType.ValueString() != "INET_DOMAIN" {
+		return false
+	}
+	switch s.VerificationMethod.ValueString() {
+	case "DNS_TXT", "DNS_CNAME":
+		return true
+	}
+	return false
+}
+
+// DNSRecordSet returns the record set that verifies the site.
+func (s *SiteVerificationResourceModel) DNSRecordSet() (*dnsv2.ResourceRecordSet, error) {
+	ttl := int64(defaultDNSTTL)
+	if !s.DNSTTL.IsNull() && !s.DNSTTL.IsUnknown() {
+		ttl = s.DNSTTL.ValueInt64()
+	}
+	if s.VerificationMethod.ValueString() == "DNS_CNAME" {
+		host, target, err := parseCNAMEToken(s.Token.ValueString(), s.SiteIdentifier.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		return &dnsv2.ResourceRecordSet{
+			Name:    host,
+			Rrdatas: []string{target},
+			Ttl:     ttl,
+			Type:    "CNAME",
+		}, nil
+	}
+	name := forceDot(s.SiteIdentifier.ValueString())
+	if !s.DNSRecordName.IsNull() && !s.DNSRecordName.IsUnknown() {
+		name = forceDot(s.DNSRecordName.ValueString())
+	}
+	return &dnsv2.ResourceRecordSet{
+		Name:    name,
+		Rrdatas: []string{quoteTXT(normalizeToken("DNS_TXT", s.Token.ValueString()))},
+		Ttl:     ttl,
+		Type:    "TXT",
+	}, nil
+}
+
+// SetDNSDefaults fills in the DNS record settings left unset in the configuration.
+func (s *SiteVerificationResourceModel) SetDNSDefaults() {
+	if !s.UsesDNS() {
+		if s.DNSTTL.IsUnknown() {
+			s.DNSTTL = types.Int64Null()
+		}
+		if s.DNSRecordName.IsUnknown() {
+			s.DNSRecordName = types.StringNull()
+		}
+		return
+	}
+	if s.DNSTTL.IsNull() || s.DNSTTL.IsUnknown() {
+		s.DNSTTL = types.Int64Value(defaultDNSTTL)
+	}
+	if s.DNSRecordName.IsNull() || s.DNSRecordName.IsUnknown() {
+		if s.VerificationMethod.ValueString() == "DNS_TXT" {
+			s.DNSRecordName = types.StringValue(forceDot(s.SiteIdentifier.ValueString()))
+		} else {
+			s.DNSRecordName = types.StringNull()
+		}
+	}
+}
+
+// SetOwners stores owners, keeping the spelling of those already held so that
+// the API normalizing the case of an email address does not produce a diff.
+func (s *SiteVerificationResourceModel) SetOwners(ctx context.Context, owners []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var known []string
+	if !s.Owners.IsNull() && !s.Owners.IsUnknown() {
+		diags.Append(s.Owners.ElementsAs(ctx, &known, false)...)
+	}
+	value, d := types.SetValueFrom(ctx, types.StringType, preserveOwnerSpelling(owners, known))
+	diags.Append(d...)
+	s.Owners = value
+	return diags
+}
+
+// SetMetaTag populates MetaTag from the token when using the META verification method.
+func (s *SiteVerificationResourceModel) SetMetaTag() {
+	if s.SiteType.ValueString() == "SITE" && s.VerificationMethod.ValueString() == "META" {
+		s.MetaTag = types.StringValue(metaTagFromToken(s.Token.ValueString()))
+		return
+	}
+	s.MetaTag = types.StringNull()
 }
 
 func (r *SiteVerificationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -62,6 +176,9 @@ func (r *SiteVerificationResource) Schema(ctx context.Context, req resource.Sche
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Attempts to verify a domain.",
 
+		// Version 1 changed owners from a list to a set.
+		Version: 1,
+
 		Attributes: map[string]schema.Attribute{
 			"project": schema.StringAttribute{
 				MarkdownDescription: "The project to use for verification. Defaults to the provider project.",
@@ -72,7 +189,7 @@ func (r *SiteVerificationResource) Schema(ctx context.Context, req resource.Sche
 				},
 			},
 			"verification_method": schema.StringAttribute{
-				MarkdownDescription: "The verification method to use. Defaults to DNS_TXT.",
+				MarkdownDescription: "The verification method to use. One of DNS_TXT, DNS_CNAME, FILE or META. Defaults to DNS_TXT.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
@@ -95,31 +212,101 @@ func (r *SiteVerificationResource) Schema(ctx context.Context, req resource.Sche
 				},
 			},
 			"token": schema.StringAttribute{
-				MarkdownDescription: "The verification token.",
-				Required:            true,
+				MarkdownDescription: "The verification token. Defaults to the token Google issues for `site_identifier`, `site_type` and `verification_method`, which is fetched again on every plan so that a new token updates the verification. DNS_TXT tokens may be quoted, split into several strings, or omit the `google-site-verification=` prefix; spellings of the same token are treated as equal.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					tokenValidator{},
+				},
 			},
 			"managed_zone": schema.StringAttribute{
-				MarkdownDescription: "The managed zone to use for DNS verification. Defaults to the first managed zone found.",
-				Required:            true,
+				MarkdownDescription: "The managed zone to use for DNS verification. Defaults to the public managed zone in the project with the longest DNS name containing `site_identifier`.",
+				Optional:            true,
+				Computed:            true,
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
-			"owners": schema.ListAttribute{
-				MarkdownDescription: "The owners of the site. Defaults to the current user.",
+			"dns_ttl": schema.Int64Attribute{
+				MarkdownDescription: "The TTL, in seconds, of the verification record set. Must be positive. Applies to the whole record set, including values managed outside this resource. Defaults to 60 for the DNS_TXT and DNS_CNAME verification methods.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					dnsTTLDefault{},
+				},
+				Validators: []validator.Int64{
+					dnsTTLValidator{},
+				},
+			},
+			"dns_record_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the verification TXT record, for example when it lives in a delegated subzone or differs from the zone apex. Defaults to `site_identifier`. Only used with the DNS_TXT verification method.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					dnsRecordNameDefault{},
+				},
+			},
+			"propagation_timeout": schema.Int64Attribute{
+				MarkdownDescription: "How long to wait, in seconds, for the authoritative nameservers of the managed zone to serve the verification record before attempting verification. Defaults to 300. Set to 0 to disable the wait.",
+				Optional:            true,
+			},
+			"propagation_interval": schema.Int64Attribute{
+				MarkdownDescription: "How often, in seconds, to query the nameservers while waiting for propagation. Defaults to 10.",
+				Optional:            true,
+			},
+			"verification_retries": schema.Int64Attribute{
+				MarkdownDescription: "How many times to retry verification, with exponential backoff, while Google cannot yet find the verification token. Defaults to 5.",
+				Optional:            true,
+			},
+			"owners": schema.SetAttribute{
+				MarkdownDescription: "The email addresses of the owners of the site, compared case-insensitively. Defaults to the current user.",
 				Optional:            true,
 				Computed:            true,
 				ElementType:         types.StringType,
-				PlanModifiers: []planmodifier.List{
-					listplanmodifier.UseStateForUnknown(),
-					// listplanmodifier.RequiresReplace(),
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"web_root": schema.SingleNestedAttribute{
+				MarkdownDescription: "Where to publish the HTML verification file when using the FILE verification method. The file is uploaded before verification and removed on destroy.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"gcs_bucket": schema.StringAttribute{
+						MarkdownDescription: "The Cloud Storage bucket serving the root of the site.",
+						Required:            true,
+					},
+					"object_prefix": schema.StringAttribute{
+						MarkdownDescription: "An optional prefix to prepend to the verification file object name.",
+						Optional:            true,
+					},
 				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+			},
+			"meta_tag": schema.StringAttribute{
+				MarkdownDescription: "The meta element to embed in the head of the site when using the META verification method.",
+				Computed:            true,
+			},
+			"verify_meta_tag": schema.BoolAttribute{
+				MarkdownDescription: "Whether to check that `site_identifier` serves the verification meta tag before attempting verification. Only used with the META verification method.",
+				Optional:            true,
 			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "The ID of the site.",
 				Computed:            true,
 			},
 		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
@@ -153,30 +340,100 @@ func (r *SiteVerificationResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	if data.SiteType.IsNull() {
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	if data.SiteType.IsNull() || data.SiteType.IsUnknown() {
 		data.SiteType = types.StringValue("INET_DOMAIN")
 	}
 
-	if data.VerificationMethod.IsNull() {
+	if data.VerificationMethod.IsNull() || data.VerificationMethod.IsUnknown() {
 		data.VerificationMethod = types.StringValue("DNS_TXT")
 	}
 
-	if data.SiteType.ValueString() == "INET_DOMAIN" {
-		if data.VerificationMethod.ValueString() == "DNS_TXT" {
-			if data.Project.IsNull() {
-				data.Project = types.StringValue(r.Clients.ProjectID)
+	if data.Project.IsNull() || data.Project.IsUnknown() {
+		data.Project = types.StringValue(r.Clients.ProjectID)
+	}
+
+	if data.Token.IsNull() || data.Token.IsUnknown() {
+		if !r.fetchToken(ctx, &resp.Diagnostics, data) {
+			return
+		}
+	}
+
+	data.SetDNSDefaults()
+
+	if data.UsesDNS() {
+		if data.ManagedZone.IsNull() || data.ManagedZone.IsUnknown() {
+			record, err := data.DNSRecordSet()
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("token"), "Invalid verification token", err.Error())
+				return
 			}
-			err := r.createDNSRecord(ctx, data)
+			zone, err := discoverManagedZone(ctx, r.Clients.DNS, data.Project.ValueString(), record.Name)
 			if err != nil {
-				resp.Diagnostics.AddError("Error creating DNS record", err.Error())
+				resp.Diagnostics.AddAttributeError(path.Root("managed_zone"), "Unable to discover managed zone", fmt.Sprintf("Set managed_zone explicitly: %s", err.Error()))
 				return
 			}
+			data.ManagedZone = types.StringValue(zone)
+		}
+		err := r.createDNSRecord(ctx, data)
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error creating DNS record", err, r.Clients.DNSIdentity))
+			return
+		}
+		err = r.waitForDNSPropagation(ctx, data)
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error waiting for DNS propagation", err, r.Clients.DNSIdentity))
+			r.cleanupVerificationArtifacts(ctx, &resp.Diagnostics, data)
+			return
 		}
 	}
 
+	if data.SiteType.ValueString() == "SITE" {
+		if data.VerificationMethod.ValueString() == "FILE" {
+			err := r.createVerificationFile(ctx, data)
+			if err != nil {
+				resp.Diagnostics.Append(apiErrorDiagnostic("Error publishing verification file", err, r.Clients.StorageIdentity))
+				return
+			}
+			err = r.waitForVerificationFile(ctx, data)
+			if err != nil {
+				resp.Diagnostics.Append(apiErrorDiagnostic("Error waiting for verification file", err, r.Clients.StorageIdentity))
+				r.cleanupVerificationArtifacts(ctx, &resp.Diagnostics, data)
+				return
+			}
+		}
+		if data.VerificationMethod.ValueString() == "META" && data.VerifyMetaTag.ValueBool() {
+			err := r.checkMetaTag(ctx, data)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("site_identifier"),
+					"Verification meta tag not found",
+					fmt.Sprintf("The site did not serve %s: %s", metaTagFromToken(data.Token.ValueString()), err.Error()),
+				)
+				return
+			}
+		}
+	}
+
+	if data.ManagedZone.IsUnknown() {
+		data.ManagedZone = types.StringNull()
+	}
+
+	data.SetMetaTag()
+
 	err := r.insertSiteVerification(ctx, resp.Diagnostics, data)
 	if err != nil {
-		resp.Diagnostics.AddError("Error inserting site verification", err.Error())
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error inserting site verification", err, r.Clients.SiteVerificationIdentity))
+		r.cleanupVerificationArtifacts(ctx, &resp.Diagnostics, data)
 		return
 	}
 
@@ -194,19 +451,29 @@ func (r *SiteVerificationResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	if data.SiteType.ValueString() == "INET_DOMAIN" {
-		if data.VerificationMethod.ValueString() == "DNS_TXT" {
-			tflog.Trace(ctx, "Looking up TXT verification record for name", map[string]any{"name": data.SiteIdentifier.ValueString(), "zone": data.ManagedZone.ValueString()})
-			err := r.readDNSRecord(ctx, data)
-			if err != nil {
-				if strings.Contains(err.Error(), "404") {
-					tflog.Trace(ctx, "DNS TXT Record not found", map[string]any{"id": data.ID.String()})
-					resp.State.RemoveResource(ctx)
-					return
-				}
-				resp.Diagnostics.AddError("Error reading DNS record", err.Error())
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	data.SetDNSDefaults()
+
+	if data.UsesDNS() {
+		tflog.Trace(ctx, "Looking up verification record for name", map[string]any{"name": data.SiteIdentifier.ValueString(), "zone": data.ManagedZone.ValueString()})
+		err := r.readDNSRecord(ctx, data)
+		if err != nil {
+			if isNotFound(err) {
+				tflog.Trace(ctx, "DNS Record not found", map[string]any{"id": data.ID.String()})
+				resp.State.RemoveResource(ctx)
 				return
 			}
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error reading DNS record", err, r.Clients.DNSIdentity))
+			return
 		}
 	}
 
@@ -214,14 +481,15 @@ func (r *SiteVerificationResource) Read(ctx context.Context, req resource.ReadRe
 		"id":   data.ID.String(),
 		"site": data.SiteIdentifier.ValueString(),
 	})
+	data.SetMetaTag()
 	err := r.readSiteVerification(ctx, resp.Diagnostics, data)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFound(err) {
 			tflog.Trace(ctx, "Site verification not found", map[string]any{"id": data.ID.String()})
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError("Error reading site verification", err.Error())
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading site verification", err, r.Clients.SiteVerificationIdentity))
 		return
 	}
 
@@ -239,36 +507,83 @@ func (r *SiteVerificationResource) Update(ctx context.Context, req resource.Upda
 		"owners": data.Owners.String(),
 	})
 
+	// Read Terraform prior state data to clean up the previous token
+	var state *SiteVerificationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if data.SiteType.IsNull() {
-		data.SiteType = types.StringValue("INET_DOMAIN")
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if data.VerificationMethod.IsNull() {
-		data.VerificationMethod = types.StringValue("DNS_TXT")
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if data.SiteType.IsNull() || data.SiteType.IsUnknown() {
+		data.SiteType = state.SiteType
 	}
 
-	if data.SiteType.ValueString() == "INET_DOMAIN" {
-		if data.VerificationMethod.ValueString() == "DNS_TXT" {
-			err := r.deleteDNSRecord(ctx, data)
-			if err != nil {
-				resp.Diagnostics.AddError("Error deleting DNS record", err.Error())
-				return
-			}
-			err = r.createDNSRecord(ctx, data)
-			if err != nil {
-				resp.Diagnostics.AddError("Error updating DNS TXT record", err.Error())
-				return
+	if data.VerificationMethod.IsNull() || data.VerificationMethod.IsUnknown() {
+		data.VerificationMethod = state.VerificationMethod
+	}
+
+	if data.Project.IsNull() || data.Project.IsUnknown() {
+		data.Project = state.Project
+	}
+
+	if data.Token.IsNull() || data.Token.IsUnknown() {
+		if !r.fetchToken(ctx, &resp.Diagnostics, data) {
+			return
+		}
+	}
+
+	if data.ManagedZone.IsUnknown() {
+		data.ManagedZone = state.ManagedZone
+	}
+
+	data.SetDNSDefaults()
+
+	if data.UsesDNS() {
+		err := r.replaceDNSRecord(ctx, state, data)
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error updating DNS record", err, r.Clients.DNSIdentity))
+			return
+		}
+	}
+
+	if data.SiteType.ValueString() == "SITE" {
+		if data.VerificationMethod.ValueString() == "FILE" {
+			if !tokensEqual("FILE", state.Token.ValueString(), data.Token.ValueString()) {
+				err := r.createVerificationFile(ctx, data)
+				if err != nil {
+					resp.Diagnostics.Append(apiErrorDiagnostic("Error publishing verification file", err, r.Clients.StorageIdentity))
+					return
+				}
+				err = r.waitForVerificationFile(ctx, data)
+				if err != nil {
+					resp.Diagnostics.Append(apiErrorDiagnostic("Error waiting for verification file", err, r.Clients.StorageIdentity))
+					return
+				}
+				err = r.deleteVerificationFile(ctx, state)
+				if err != nil {
+					resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting previous verification file", err, r.Clients.StorageIdentity))
+					return
+				}
 			}
 		}
 	}
 
+	data.SetMetaTag()
+
 	err := r.patchSiteVerification(ctx, resp.Diagnostics, data)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating site verification", err.Error())
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating site verification", err, r.Clients.SiteVerificationIdentity))
 	}
 
 	// Save updated data into Terraform state
@@ -285,34 +600,112 @@ func (r *SiteVerificationResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	if data.SiteType.ValueString() == "INET_DOMAIN" {
-		if data.VerificationMethod.ValueString() == "DNS_TXT" {
-			err := r.deleteDNSRecord(ctx, data)
-			if err != nil {
-				resp.Diagnostics.AddError("Error deleting DNS record", err.Error())
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if data.UsesDNS() {
+		err := r.deleteDNSRecord(ctx, data)
+		if err != nil && !isNotFound(err) {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting DNS record", err, r.Clients.DNSIdentity))
+			return
+		}
+		tflog.Trace(ctx, "DNS record deleted")
+	}
+
+	if data.SiteType.ValueString() == "SITE" {
+		if data.VerificationMethod.ValueString() == "FILE" {
+			err := r.deleteVerificationFile(ctx, data)
+			if err != nil && !isNotFound(err) {
+				resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting verification file", err, r.Clients.StorageIdentity))
 				return
 			}
-			tflog.Trace(ctx, "DNS record deleted")
+			tflog.Trace(ctx, "Verification file deleted")
 		}
 	}
 
 	err := r.deleteSiteVerification(ctx, data)
-	if err != nil {
-		resp.Diagnostics.AddError("Error relinquishing site verification", err.Error())
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error relinquishing site verification", err, r.Clients.SiteVerificationIdentity))
+	}
+}
+
+// ModifyPlan fetches the verification token when it is not configured, so that
+// a change of token on Google's side plans an update of the verification.
+func (r *SiteVerificationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy, or before the provider has been configured.
+	if req.Plan.Raw.IsNull() || r.Clients == nil {
+		return
+	}
+
+	var config *SiteVerificationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Token.IsNull() {
+		return
+	}
+
+	// The token cannot be fetched until the site is known; it is fetched on apply instead.
+	if config.SiteIdentifier.IsUnknown() || config.SiteType.IsUnknown() || config.VerificationMethod.IsUnknown() {
+		return
+	}
+
+	if config.SiteType.IsNull() {
+		config.SiteType = types.StringValue("INET_DOMAIN")
 	}
+
+	if config.VerificationMethod.IsNull() {
+		config.VerificationMethod = types.StringValue("DNS_TXT")
+	}
+
+	if !r.fetchToken(ctx, &resp.Diagnostics, config) {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("token"), config.Token)...)
 }
 
 func (r *SiteVerificationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+// fetchToken sets the token of data to the one Google issues for its site and
+// verification method, reporting whether it succeeded.
+func (r *SiteVerificationResource) fetchToken(ctx context.Context, diags *diag.Diagnostics, data *SiteVerificationResourceModel) bool {
+	token, err := getVerificationToken(ctx, r.Clients.SiteVerification, data.SiteType.ValueString(), data.SiteIdentifier.ValueString(), data.VerificationMethod.ValueString())
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Error retrieving verification token", err, r.Clients.SiteVerificationIdentity))
+		return false
+	}
+	data.Token = types.StringValue(token)
+	return true
+}
+
+// lockZone serializes DNS changes to the managed zone of data across resources.
+func (r *SiteVerificationResource) lockZone(ctx context.Context, data *SiteVerificationResourceModel) func() {
+	key := zoneLockKey(data.Project.ValueString(), data.ManagedZone.ValueString())
+	tflog.Trace(ctx, "Waiting for managed zone lock", map[string]any{"key": key})
+	r.Clients.ZoneLocks.Lock(key)
+	return func() {
+		r.Clients.ZoneLocks.Unlock(key)
+	}
+}
+
 func (r *SiteVerificationResource) createDNSRecord(ctx context.Context, data *SiteVerificationResourceModel) error {
-	record := &dnsv2.ResourceRecordSet{
-		Name:    forceDot(data.SiteIdentifier.ValueString()),
-		Rrdatas: []string{data.Token.ValueString()},
-		Ttl:     60,
-		Type:    "TXT",
+	record, err := data.DNSRecordSet()
+	if err != nil {
+		return err
 	}
+	defer r.lockZone(ctx, data)()
 	tflog.Trace(ctx, "Creating DNS record", map[string]any{
 		"id":      data.ID.String(),
 		"site":    data.SiteIdentifier.ValueString(),
@@ -321,22 +714,18 @@ func (r *SiteVerificationResource) createDNSRecord(ctx context.Context, data *Si
 		"token":   data.Token.ValueString(),
 		"record":  record,
 	})
-	gresp, err := r.Clients.DNS.ResourceRecordSets.Create(
-		data.Project.ValueString(),
-		"global",
-		data.ManagedZone.ValueString(),
-		record,
-	).Context(ctx).Do()
-	if err != nil {
+	change := &dnsv2.Change{}
+	if err := r.planRecordChange(ctx, data, change, record, nil, record.Rrdatas); err != nil {
 		return err
 	}
-	tflog.Trace(ctx, "DNS record created", map[string]any{
-		"response": gresp,
-	})
-	return nil
+	return r.applyDNSChange(ctx, data, change)
 }
 
 func (r *SiteVerificationResource) readDNSRecord(ctx context.Context, data *SiteVerificationResourceModel) error {
+	record, err := data.DNSRecordSet()
+	if err != nil {
+		return err
+	}
 	tflog.Trace(ctx, "Looking up DNS record", map[string]any{
 		"id":      data.ID.String(),
 		"site":    data.SiteIdentifier.ValueString(),
@@ -347,39 +736,157 @@ func (r *SiteVerificationResource) readDNSRecord(ctx context.Context, data *Site
 		data.Project.ValueString(),
 		"global",
 		data.ManagedZone.ValueString(),
-		data.SiteIdentifier.ValueString(),
-		"TXT",
+		record.Name,
+		record.Type,
 	).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
+	if record.Type == "TXT" {
+		// The record set may hold unrelated values, only check for our own.
+		data.DNSTTL = types.Int64Value(gresp.Ttl)
+		for _, rrdata := range gresp.Rrdatas {
+			if txtValuesEqual(rrdata, record.Rrdatas[0]) {
+				return nil
+			}
+		}
+		tflog.Trace(ctx, "Verification token missing from TXT record set", map[string]any{
+			"rrdatas": gresp.Rrdatas,
+		})
+		data.Token = types.StringValue("")
+		return nil
+	}
 	if len(gresp.Rrdatas) != 1 {
-		return fmt.Errorf("Expected 1 TXT record, got %d", len(gresp.Rrdatas))
+		return fmt.Errorf("Expected 1 %s record, got %d", record.Type, len(gresp.Rrdatas))
+	}
+	data.DNSTTL = types.Int64Value(gresp.Ttl)
+	if record.Type == "CNAME" {
+		// Only surface a new token when the target drifted, so that equivalent
+		// spellings of the same token do not produce a diff.
+		if forceDot(gresp.Rrdatas[0]) != forceDot(record.Rrdatas[0]) {
+			host, _, _ := parseCNAMEToken(data.Token.ValueString(), data.SiteIdentifier.ValueString())
+			data.Token = types.StringValue(fmt.Sprintf("%s %s", strings.TrimSuffix(host, "."), gresp.Rrdatas[0]))
+		}
+		return nil
 	}
-	data.Token = types.StringValue(strings.Trim(gresp.Rrdatas[0], `"`))
 	return nil
 }
 
 func (r *SiteVerificationResource) deleteDNSRecord(ctx context.Context, data *SiteVerificationResourceModel) error {
+	record, err := data.DNSRecordSet()
+	if err != nil {
+		return err
+	}
+	defer r.lockZone(ctx, data)()
 	tflog.Trace(ctx, "Deleting DNS record", map[string]any{
 		"id":      data.ID.String(),
 		"site":    data.SiteIdentifier.ValueString(),
 		"zone":    data.ManagedZone.ValueString(),
 		"project": data.Project.ValueString(),
 	})
-	err := r.Clients.DNS.ResourceRecordSets.Delete(
-		data.Project.ValueString(),
-		"global",
-		data.ManagedZone.ValueString(),
-		data.SiteIdentifier.ValueString(),
-		"TXT",
-	).Context(ctx).Do()
+	change := &dnsv2.Change{}
+	if err := r.planRecordChange(ctx, data, change, record, record.Rrdatas, nil); err != nil {
+		return err
+	}
+	return r.applyDNSChange(ctx, data, change)
+}
+
+// replaceDNSRecord swaps the verification record described by prior for the
+// one described by data in a single change, so the zone is never left without
+// a verification record. Both must be in the same managed zone.
+func (r *SiteVerificationResource) replaceDNSRecord(ctx context.Context, prior, data *SiteVerificationResourceModel) error {
+	oldRecord, err := prior.DNSRecordSet()
+	if err != nil {
+		return err
+	}
+	newRecord, err := data.DNSRecordSet()
+	if err != nil {
+		return err
+	}
+	defer r.lockZone(ctx, data)()
+	tflog.Trace(ctx, "Replacing DNS record", map[string]any{
+		"id":   data.ID.String(),
+		"zone": data.ManagedZone.ValueString(),
+		"old":  oldRecord,
+		"new":  newRecord,
+	})
+	change := &dnsv2.Change{}
+	if oldRecord.Name == newRecord.Name && oldRecord.Type == newRecord.Type {
+		err = r.planRecordChange(ctx, data, change, newRecord, oldRecord.Rrdatas, newRecord.Rrdatas)
+	} else {
+		err = r.planRecordChange(ctx, data, change, oldRecord, oldRecord.Rrdatas, nil)
+		if err == nil {
+			err = r.planRecordChange(ctx, data, change, newRecord, nil, newRecord.Rrdatas)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return r.applyDNSChange(ctx, data, change)
+}
+
+func (r *SiteVerificationResource) createVerificationFile(ctx context.Context, data *SiteVerificationResourceModel) error {
+	backend, err := newWebRootBackend(ctx, r.Clients, data.WebRoot)
+	if err != nil {
+		return err
+	}
+	return backend.PutFile(ctx, data.Token.ValueString(), verificationFileContent(data.Token.ValueString()))
+}
+
+// waitForVerificationFile waits until the site serves the verification file
+// published by createVerificationFile.
+func (r *SiteVerificationResource) waitForVerificationFile(ctx context.Context, data *SiteVerificationResourceModel) error {
+	content := verificationFileContent(data.Token.ValueString())
+	url := verificationFileURL(data.SiteIdentifier.ValueString(), data.Token.ValueString())
+	tflog.Trace(ctx, "Waiting for verification file to be served", map[string]any{
+		"url": url,
+	})
+	return waitForURLContent(ctx, url, content)
+}
+
+func (r *SiteVerificationResource) deleteVerificationFile(ctx context.Context, data *SiteVerificationResourceModel) error {
+	backend, err := newWebRootBackend(ctx, r.Clients, data.WebRoot)
 	if err != nil {
 		return err
 	}
+	return backend.DeleteFile(ctx, data.Token.ValueString())
+}
+
+// checkMetaTag fetches the site once and checks that it serves the
+// verification meta element, so that a missing tag fails the apply early.
+func (r *SiteVerificationResource) checkMetaTag(ctx context.Context, data *SiteVerificationResourceModel) error {
+	tflog.Trace(ctx, "Checking verification meta tag is served", map[string]any{
+		"url": data.SiteIdentifier.ValueString(),
+	})
+	body, err := fetchURL(ctx, data.SiteIdentifier.ValueString())
+	if err != nil {
+		return err
+	}
+	if !hasVerificationMetaTag(body, metaTagContent(data.Token.ValueString())) {
+		return fmt.Errorf("no google-site-verification meta element with the expected content found")
+	}
 	return nil
 }
 
+// cleanupVerificationArtifacts removes the DNS record or verification file
+// published for a verification attempt that failed at any later step, so that
+// it is not orphaned outside of Terraform state. The attempt may have failed because
+// the Create timeout expired, so cleanup runs on a fresh context of its own.
+func (r *SiteVerificationResource) cleanupVerificationArtifacts(_ context.Context, diags *diag.Diagnostics, data *SiteVerificationResourceModel) {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+	var err error
+	switch {
+	case data.UsesDNS():
+		err = r.deleteDNSRecord(ctx, data)
+	case data.SiteType.ValueString() == "SITE" && data.VerificationMethod.ValueString() == "FILE":
+		err = r.deleteVerificationFile(ctx, data)
+	}
+	if err != nil {
+		diags.AddWarning("Failed to clean up after failed verification", err.Error())
+	}
+}
+
 func (r *SiteVerificationResource) insertSiteVerification(ctx context.Context, diag diag.Diagnostics, data *SiteVerificationResourceModel) error {
 	tflog.Trace(ctx, "Inserting site verification", map[string]any{
 		"id":   data.ID.String(),
@@ -392,7 +899,15 @@ func (r *SiteVerificationResource) insertSiteVerification(ctx context.Context, d
 	tflog.Trace(ctx, "Request", map[string]any{
 		"request": greq,
 	})
-	callResp, err := r.Clients.SiteVerification.WebResource.Insert(data.VerificationMethod.ValueString(), greq).Context(ctx).Do()
+	retries := defaultVerificationRetries
+	if !data.VerificationRetries.IsNull() {
+		retries = int(data.VerificationRetries.ValueInt64())
+	}
+	var callResp *sitev1.SiteVerificationWebResourceResource
+	err = retryVerification(ctx, retries, func() error {
+		callResp, err = r.Clients.SiteVerification.WebResource.Insert(data.VerificationMethod.ValueString(), greq).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -402,9 +917,7 @@ func (r *SiteVerificationResource) insertSiteVerification(ctx context.Context, d
 		"id":     callResp.Id,
 	})
 	// Extract owners from response
-	owners, diags := parseOwnersFromResponse(callResp)
-	diag.Append(diags...)
-	data.Owners = owners
+	diag.Append(data.SetOwners(ctx, callResp.Owners)...)
 	// Extract ID from response
 	id, err := decodeID(callResp.Id)
 	if err != nil {
@@ -420,7 +933,7 @@ func (r *SiteVerificationResource) readSiteVerification(ctx context.Context, dia
 		"id":   data.ID.String(),
 		"site": data.SiteIdentifier.ValueString(),
 	})
-	resp, err := r.Clients.SiteVerification.WebResource.Get(data.SiteID()).Context(ctx).Do()
+	resp, err := r.Clients.SiteVerification.WebResource.Get(data.WebResourceID()).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
@@ -428,36 +941,36 @@ func (r *SiteVerificationResource) readSiteVerification(ctx context.Context, dia
 		"status": resp.ServerResponse.HTTPStatusCode,
 		"owners": resp.Owners,
 	})
-	owners, diags := parseOwnersFromResponse(resp)
-	diag.Append(diags...)
-	data.Owners = owners
+	diag.Append(data.SetOwners(ctx, resp.Owners)...)
 	return nil
 }
 
 func (r *SiteVerificationResource) patchSiteVerification(ctx context.Context, diag diag.Diagnostics, data *SiteVerificationResourceModel) error {
+	id := data.WebResourceID()
 	tflog.Trace(ctx, "Patching site verification", map[string]any{
-		"id":   data.ID.String(),
+		"id":   id,
 		"site": data.SiteIdentifier.ValueString(),
 	})
-	greq, err := r.buildSiteVerification(ctx, data, false)
-	if err != nil {
-		return err
+	var desired []string
+	if !data.Owners.IsNull() && !data.Owners.IsUnknown() {
+		var err error
+		desired, err = parseOwnersFromData(ctx, data)
+		if err != nil {
+			return err
+		}
 	}
-	tflog.Trace(ctx, "Request", map[string]any{
-		"request": greq,
+	// Share the lock and conflict retries of the owner resources.
+	owners, err := updateSiteOwners(ctx, r.Clients, id, func(owners []string) ([]string, bool) {
+		if desired == nil {
+			return owners, false
+		}
+		return desired, !sameOwners(owners, desired)
 	})
-	callResp, err := r.Clients.SiteVerification.WebResource.Patch(data.SiteID(), greq).Context(ctx).Do()
 	if err != nil {
 		return err
 	}
-	tflog.Trace(ctx, "Response", map[string]any{
-		"status": callResp.ServerResponse.HTTPStatusCode,
-		"id":     callResp.Id,
-	})
 	// Extract owners from response
-	owns, diags := stringSliceToListValue(greq.Owners)
-	diag.Append(diags...)
-	data.Owners = owns
+	diag.Append(data.SetOwners(ctx, owners)...)
 	return nil
 }
 
@@ -484,5 +997,5 @@ func (r *SiteVerificationResource) deleteSiteVerification(ctx context.Context, d
 		"id":   data.ID.ValueString(),
 		"site": data.SiteIdentifier.ValueString(),
 	})
-	return r.Clients.SiteVerification.WebResource.Delete(data.SiteID()).Context(ctx).Do()
+	return r.Clients.SiteVerification.WebResource.Delete(data.WebResourceID()).Context(ctx).Do()
 }
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// UpgradeState upgrades state saved by earlier versions of the resource schema.
+func (r *SiteVerificationResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	// Version 0 differs from the current schema only in owners being a list.
+	schemaV0 := current.Schema
+	schemaV0.Version = 0
+	schemaV0.Attributes = make(map[string]schema.Attribute, len(current.Schema.Attributes))
+	for name, attribute := range current.Schema.Attributes {
+		schemaV0.Attributes[name] = attribute
+	}
+	schemaV0.Attributes["owners"] = schema.ListAttribute{
+		Optional:    true,
+		Computed:    true,
+		ElementType: types.StringType,
+	}
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeSiteVerificationStateV0,
+		},
+	}
+}
+
+// upgradeSiteVerificationStateV0 converts the owners list to a set, dropping
+// owners that only differ in case, and carries every other attribute over.
+func upgradeSiteVerificationStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var attributes map[string]tftypes.Value
+	if err := req.State.Raw.As(&attributes); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Failed to read prior state: %s", err))
+		return
+	}
+
+	setType := tftypes.Set{ElementType: tftypes.String}
+	owners := attributes["owners"]
+	switch {
+	case owners.IsNull():
+		attributes["owners"] = tftypes.NewValue(setType, nil)
+	case !owners.IsKnown():
+		attributes["owners"] = tftypes.NewValue(setType, tftypes.UnknownValue)
+	default:
+		var elements []tftypes.Value
+		if err := owners.As(&elements); err != nil {
+			resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Failed to read prior owners: %s", err))
+			return
+		}
+		var seen []string
+		unique := []tftypes.Value{}
+		for _, element := range elements {
+			var owner string
+			if err := element.As(&owner); err != nil {
+				resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Failed to read prior owner: %s", err))
+				return
+			}
+			if containsOwner(seen, owner) {
+				continue
+			}
+			seen = append(seen, owner)
+			unique = append(unique, element)
+		}
+		attributes["owners"] = tftypes.NewValue(setType, unique)
+	}
+
+	resp.State.Raw = tftypes.NewValue(resp.State.Schema.Type().TerraformType(ctx), attributes)
+}
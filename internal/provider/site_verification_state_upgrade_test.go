@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// upgradeSiteVerificationV0 runs a version 0 state, with owners set to
+// owners and every other attribute given a non-null value, through the state
+// upgrader, returning the prior and upgraded attributes.
+func upgradeSiteVerificationV0(t *testing.T, owners tftypes.Value) (map[string]tftypes.Value, map[string]tftypes.Value) {
+	t.Helper()
+	ctx := context.Background()
+	r := &SiteVerificationResource{}
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("no state upgrader for version 0")
+	}
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+
+	prior := map[string]tftypes.Value{}
+	for name, attrType := range priorType.AttributeTypes {
+		switch {
+		case name == "owners":
+			prior[name] = owners
+		case attrType.Is(tftypes.String):
+			prior[name] = tftypes.NewValue(tftypes.String, name+"-value")
+		case attrType.Is(tftypes.Number):
+			prior[name] = tftypes.NewValue(tftypes.Number, big.NewFloat(float64(len(name))))
+		case attrType.Is(tftypes.Bool):
+			prior[name] = tftypes.NewValue(tftypes.Bool, true)
+		case attrType.Is(tftypes.Object{}):
+			objectType := attrType.(tftypes.Object)
+			attributes := map[string]tftypes.Value{}
+			for nestedName, nestedType := range objectType.AttributeTypes {
+				if nestedType.Is(tftypes.String) {
+					attributes[nestedName] = tftypes.NewValue(nestedType, nestedName+"-value")
+				} else {
+					attributes[nestedName] = tftypes.NewValue(nestedType, nil)
+				}
+			}
+			prior[name] = tftypes.NewValue(objectType, attributes)
+		default:
+			t.Fatalf("unexpected type %s for attribute %s", attrType, name)
+		}
+	}
+
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{
+			Schema: *upgrader.PriorSchema,
+			Raw:    tftypes.NewValue(priorType, prior),
+		},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: current.Schema,
+		},
+	}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if !resp.State.Raw.Type().Equal(current.Schema.Type().TerraformType(ctx)) {
+		t.Fatalf("upgraded state has type %s, want the current schema type", resp.State.Raw.Type())
+	}
+	var upgraded map[string]tftypes.Value
+	if err := resp.State.Raw.As(&upgraded); err != nil {
+		t.Fatal(err)
+	}
+	return prior, upgraded
+}
+
+func TestUpgradeSiteVerificationStateV0(t *testing.T) {
+	listType := tftypes.List{ElementType: tftypes.String}
+	setType := tftypes.Set{ElementType: tftypes.String}
+
+	owners := tftypes.NewValue(listType, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "Alice@example.com"),
+		tftypes.NewValue(tftypes.String, "bob@example.com"),
+		tftypes.NewValue(tftypes.String, "alice@example.com"),
+	})
+	prior, upgraded := upgradeSiteVerificationV0(t, owners)
+
+	want := tftypes.NewValue(setType, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "Alice@example.com"),
+		tftypes.NewValue(tftypes.String, "bob@example.com"),
+	})
+	if !upgraded["owners"].Equal(want) {
+		t.Errorf("got owners %s, want %s", upgraded["owners"], want)
+	}
+
+	if len(upgraded) != len(prior) {
+		t.Errorf("got %d attributes, want %d", len(upgraded), len(prior))
+	}
+	for name, value := range prior {
+		if name == "owners" {
+			continue
+		}
+		if !upgraded[name].Equal(value) {
+			t.Errorf("attribute %s: got %s, want %s", name, upgraded[name], value)
+		}
+	}
+}
+
+func TestUpgradeSiteVerificationStateV0NullAndUnknownOwners(t *testing.T) {
+	listType := tftypes.List{ElementType: tftypes.String}
+	setType := tftypes.Set{ElementType: tftypes.String}
+
+	tests := map[string]struct {
+		owners tftypes.Value
+		want   tftypes.Value
+	}{
+		"null": {
+			owners: tftypes.NewValue(listType, nil),
+			want:   tftypes.NewValue(setType, nil),
+		},
+		"unknown": {
+			owners: tftypes.NewValue(listType, tftypes.UnknownValue),
+			want:   tftypes.NewValue(setType, tftypes.UnknownValue),
+		},
+		"empty": {
+			owners: tftypes.NewValue(listType, []tftypes.Value{}),
+			want:   tftypes.NewValue(setType, []tftypes.Value{}),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, upgraded := upgradeSiteVerificationV0(t, tt.owners)
+			if !upgraded["owners"].Equal(tt.want) {
+				t.Errorf("got owners %s, want %s", upgraded["owners"], tt.want)
+			}
+		})
+	}
+}
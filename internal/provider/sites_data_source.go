@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	sitev1 "google.golang.org/api/siteverification/v1"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SitesDataSource{}
+
+func NewSitesDataSource() datasource.DataSource {
+	return &SitesDataSource{}
+}
+
+// SitesDataSource defines the data source implementation.
+type SitesDataSource struct {
+	client   *sitev1.Service
+	identity string
+}
+
+// SitesDataSourceModel describes the data source data model.
+type SitesDataSourceModel struct {
+	SiteType         types.String               `tfsdk:"site_type"`
+	IdentifierSuffix types.String               `tfsdk:"identifier_suffix"`
+	IdentifierRegex  types.String               `tfsdk:"identifier_regex"`
+	Owner            types.String               `tfsdk:"owner"`
+	Sites            []SitesDataSourceSiteModel `tfsdk:"sites"`
+}
+
+// SitesDataSourceSiteModel describes a verified site in the data source data model.
+type SitesDataSourceSiteModel struct {
+	ID             types.String `tfsdk:"id"`
+	SiteIdentifier types.String `tfsdk:"site_identifier"`
+	SiteType       types.String `tfsdk:"site_type"`
+	Owners         types.List   `tfsdk:"owners"`
+}
+
+// siteMatchesSuffix reports whether site is the domain suffix or lies below
+// it. URLs are matched on their host.
+func siteMatchesSuffix(site *sitev1.SiteVerificationWebResourceResourceSite, suffix string) bool {
+	name := site.Identifier
+	if site.Type == "SITE" {
+		u, err := url.Parse(site.Identifier)
+		if err != nil || u.Hostname() == "" {
+			return false
+		}
+		name = u.Hostname()
+	}
+	return zoneMatchesName(suffix, name)
+}
+
+func (d *SitesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sites"
+}
+
+func (d *SitesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists the sites verified by the credentials the provider is using.",
+
+		Attributes: map[string]schema.Attribute{
+			"site_type": schema.StringAttribute{
+				MarkdownDescription: "Only list sites of this type, either INET_DOMAIN or SITE.",
+				Optional:            true,
+			},
+			"identifier_suffix": schema.StringAttribute{
+				MarkdownDescription: "Only list sites at or below this domain, matching URLs on their host. Labels are matched whole, so `example.com` does not match `badexample.com`. Compared case-insensitively, and a trailing dot is ignored.",
+				Optional:            true,
+			},
+			"identifier_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list sites whose identifier matches this regular expression.",
+				Optional:            true,
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "Only list sites with this owner. Compared case-insensitively.",
+				Optional:            true,
+			},
+			"sites": schema.ListNestedAttribute{
+				MarkdownDescription: "The verified sites.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the site.",
+							Computed:            true,
+						},
+						"site_identifier": schema.StringAttribute{
+							MarkdownDescription: "The DNS name or URL of the site.",
+							Computed:            true,
+						},
+						"site_type": schema.StringAttribute{
+							MarkdownDescription: "The type of the site.",
+							Computed:            true,
+						},
+						"owners": schema.ListAttribute{
+							MarkdownDescription: "The owners of the site.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SitesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*SiteVerificationClients)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SiteVerificationClients, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.SiteVerification
+	d.identity = data.SiteVerificationIdentity
+}
+
+func (d *SitesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SitesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var identifierRegex *regexp.Regexp
+	if !data.IdentifierRegex.IsNull() {
+		var err error
+		identifierRegex, err = regexp.Compile(data.IdentifierRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("identifier_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+
+	callResp, err := d.client.WebResource.List().Context(ctx).Do()
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error listing verified sites", err, d.identity))
+		return
+	}
+
+	tflog.Trace(ctx, "Response", map[string]any{
+		"status": callResp.ServerResponse.HTTPStatusCode,
+		"count":  len(callResp.Items),
+	})
+
+	data.Sites = []SitesDataSourceSiteModel{}
+	for _, item := range callResp.Items {
+		if item.Site == nil {
+			continue
+		}
+		if !data.SiteType.IsNull() && item.Site.Type != data.SiteType.ValueString() {
+			continue
+		}
+		if !data.IdentifierSuffix.IsNull() && !siteMatchesSuffix(item.Site, data.IdentifierSuffix.ValueString()) {
+			continue
+		}
+		if identifierRegex != nil && !identifierRegex.MatchString(item.Site.Identifier) {
+			continue
+		}
+		if !data.Owner.IsNull() && !containsOwner(item.Owners, data.Owner.ValueString()) {
+			continue
+		}
+
+		id, err := decodeID(item.Id)
+		if err != nil {
+			resp.Diagnostics.AddError("Error decoding site ID", err.Error())
+			return
+		}
+		owners, diags := parseOwnersFromResponse(item)
+		resp.Diagnostics.Append(diags...)
+		data.Sites = append(data.Sites, SitesDataSourceSiteModel{
+			ID:             types.StringValue(id),
+			SiteIdentifier: types.StringValue(item.Site.Identifier),
+			SiteType:       types.StringValue(item.Site.Type),
+			Owners:         owners,
+		})
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"testing"
+
+	sitev1 "google.golang.org/api/siteverification/v1"
+)
+
+func TestSiteMatchesSuffix(t *testing.T) {
+	domain := func(identifier string) *sitev1.SiteVerificationWebResourceResourceSite {
+		return &sitev1.SiteVerificationWebResourceResourceSite{Identifier: identifier, Type: "INET_DOMAIN"}
+	}
+	site := func(identifier string) *sitev1.SiteVerificationWebResourceResourceSite {
+		return &sitev1.SiteVerificationWebResourceResourceSite{Identifier: identifier, Type: "SITE"}
+	}
+
+	tests := map[string]struct {
+		site   *sitev1.SiteVerificationWebResourceResourceSite
+		suffix string
+		want   bool
+	}{
+		"apex":               {site: domain("example.com"), suffix: "example.com", want: true},
+		"subdomain":          {site: domain("www.example.com"), suffix: "example.com", want: true},
+		"trailing dots":      {site: domain("www.example.com."), suffix: "example.com.", want: true},
+		"case":               {site: domain("WWW.Example.COM"), suffix: "example.com", want: true},
+		"partial label":      {site: domain("badexample.com"), suffix: "example.com", want: false},
+		"other domain":       {site: domain("example.org"), suffix: "example.com", want: false},
+		"parent":             {site: domain("com"), suffix: "example.com", want: false},
+		"url host":           {site: site("https://www.example.com/"), suffix: "example.com", want: true},
+		"url with port":      {site: site("http://example.com:8080/path"), suffix: "example.com", want: true},
+		"url partial label":  {site: site("https://badexample.com/"), suffix: "example.com", want: false},
+		"url path only":      {site: site("https://other.org/example.com"), suffix: "example.com", want: false},
+		"url without a host": {site: site("example.com"), suffix: "example.com", want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := siteMatchesSuffix(tt.site, tt.suffix); got != tt.want {
+				t.Errorf("siteMatchesSuffix(%q, %q) = %t, want %t", tt.site.Identifier, tt.suffix, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	defaultMaxRetries     = 5
+	retryInitialBackoff   = 1 * time.Second
+	retryMaxBackoff       = 30 * time.Second
+	defaultRequestTimeout = 2 * time.Minute
+)
+
+// transportConfig configures the HTTP transport shared by the provider's API clients.
+type transportConfig struct {
+	maxRetries        int
+	requestTimeout    time.Duration
+	requestsPerSecond float64
+}
+
+// newHTTPClient returns an HTTP client authenticating with creds that retries
+// and rate limits requests according to cfg. Each call gets its own limiter,
+// so limits apply per API.
+func newHTTPClient(creds *google.Credentials, cfg transportConfig) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			base: &oauth2.Transport{
+				Source: creds.TokenSource,
+				Base:   http.DefaultTransport,
+			},
+			limiter:    newRateLimiter(cfg.requestsPerSecond),
+			maxRetries: cfg.maxRetries,
+			timeout:    cfg.requestTimeout,
+		},
+	}
+}
+
+// rateLimiter spaces out requests so that no more than a fixed number start each second.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait blocks until the next request may start or ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryTransport retries requests that fail with rate limiting or server
+// errors, and applies a rate limit and per-attempt timeout to every request.
+// Only idempotent requests are retried on server errors, since the server may
+// have acted on them; 429 responses are retried for every method.
+type retryTransport struct {
+	base       http.RoundTripper
+	limiter    *rateLimiter
+	maxRetries int
+	timeout    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	getBody, err := replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if t.timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, t.timeout)
+		}
+		attemptReq := req.Clone(attemptCtx)
+		if getBody != nil {
+			if attemptReq.Body, err = getBody(); err != nil {
+				cancel()
+				return nil, err
+			}
+		}
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.maxRetries || !shouldRetry(req, resp, err) {
+			if resp != nil {
+				resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			} else {
+				cancel()
+			}
+			return resp, err
+		}
+		wait := retryBackoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+		cancel()
+		tflog.Debug(ctx, "Retrying Google API request", map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		})
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// replayableBody returns a function producing a fresh copy of the request
+// body for each attempt, buffering the body when the request cannot replay it.
+func replayableBody(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}, nil
+}
+
+// cancelOnClose releases the per-attempt context once the response body has been consumed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// retryBackoff honors a Retry-After header, falling back to exponential
+// backoff with full jitter.
+func retryBackoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if after := resp.Header.Get("Retry-After"); after != "" {
+			if secs, err := strconv.Atoi(after); err == nil && secs >= 0 {
+				return time.Duration(secs) * time.Second
+			}
+			if at, err := http.ParseTime(after); err == nil {
+				if wait := time.Until(at); wait > 0 {
+					return wait
+				}
+				return 0
+			}
+		}
+	}
+	backoff := retryInitialBackoff << attempt
+	if backoff <= 0 || backoff > retryMaxBackoff {
+		backoff = retryMaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
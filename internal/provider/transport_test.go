@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusServer answers requests with the given statuses in turn, repeating
+// the last one, and records the bodies it received.
+type statusServer struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func (s *statusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	s.bodies = append(s.bodies, string(body))
+	s.mu.Unlock()
+
+	// Keep retries in tests immediate.
+	w.Header().Set("Retry-After", "0")
+	w.WriteHeader(status)
+}
+
+func (s *statusServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func newStatusServer(t *testing.T, statuses ...int) (*statusServer, *httptest.Server) {
+	t.Helper()
+	s := &statusServer{statuses: statuses}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func testRetryTransport(maxRetries int) *retryTransport {
+	return &retryTransport{
+		base:       http.DefaultTransport,
+		maxRetries: maxRetries,
+		timeout:    10 * time.Second,
+	}
+}
+
+func TestRetryTransportRetries(t *testing.T) {
+	tests := map[string]struct {
+		method     string
+		statuses   []int
+		wantStatus int
+		wantCalls  int
+	}{
+		"get success":         {method: http.MethodGet, statuses: []int{200}, wantStatus: 200, wantCalls: 1},
+		"get server error":    {method: http.MethodGet, statuses: []int{503, 500, 200}, wantStatus: 200, wantCalls: 3},
+		"delete server error": {method: http.MethodDelete, statuses: []int{502, 204}, wantStatus: 204, wantCalls: 2},
+		"post rate limited":   {method: http.MethodPost, statuses: []int{429, 200}, wantStatus: 200, wantCalls: 2},
+		"patch rate limited":  {method: http.MethodPatch, statuses: []int{429, 429, 200}, wantStatus: 200, wantCalls: 3},
+		"post server error":   {method: http.MethodPost, statuses: []int{503, 200}, wantStatus: 503, wantCalls: 1},
+		"patch server error":  {method: http.MethodPatch, statuses: []int{500, 200}, wantStatus: 500, wantCalls: 1},
+		"client error":        {method: http.MethodGet, statuses: []int{404, 200}, wantStatus: 404, wantCalls: 1},
+		"retries exhausted":   {method: http.MethodGet, statuses: []int{429}, wantStatus: 429, wantCalls: 3},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, srv := newStatusServer(t, tt.statuses...)
+			client := &http.Client{Transport: testRetryTransport(2)}
+
+			req, err := http.NewRequest(tt.method, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := len(s.requests()); got != tt.wantCalls {
+				t.Errorf("got %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	tests := map[string]func() io.Reader{
+		// http.NewRequest sets GetBody for a strings.Reader.
+		"get body": func() io.Reader { return strings.NewReader(`{"owners":["a@example.com"]}`) },
+		// It cannot for other readers, so the transport buffers the body.
+		"buffered": func() io.Reader { return io.MultiReader(strings.NewReader(`{"owners":["a@example.com"]}`)) },
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, srv := newStatusServer(t, 429, 429, 200)
+			client := &http.Client{Transport: testRetryTransport(5)}
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL, body())
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			bodies := s.requests()
+			if len(bodies) != 3 {
+				t.Fatalf("got %d requests, want 3", len(bodies))
+			}
+			for i, b := range bodies {
+				if b != `{"owners":["a@example.com"]}` {
+					t.Errorf("attempt %d sent body %q", i+1, b)
+				}
+			}
+		})
+	}
+}
+
+// failingTransport fails every request with a network error.
+type failingTransport struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (f *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+	return nil, errors.New("connection reset")
+}
+
+func TestRetryTransportNetworkErrors(t *testing.T) {
+	tests := map[string]struct {
+		method    string
+		wantCalls int
+	}{
+		"idempotent":     {method: http.MethodGet, wantCalls: 2},
+		"not idempotent": {method: http.MethodPost, wantCalls: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// A single retry keeps the jittered backoff under retryInitialBackoff.
+			base := &failingTransport{}
+			transport := &retryTransport{base: base, maxRetries: 1}
+			req, err := http.NewRequest(tt.method, "http://example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := transport.RoundTrip(req); err == nil {
+				t.Fatal("expected an error")
+			}
+			if base.calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", base.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	withRetryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	tests := map[string]struct {
+		attempt int
+		resp    *http.Response
+		min     time.Duration
+		max     time.Duration
+	}{
+		"seconds": {
+			resp: withRetryAfter("7"),
+			min:  7 * time.Second,
+			max:  7 * time.Second,
+		},
+		"zero seconds": {
+			resp: withRetryAfter("0"),
+			min:  0,
+			max:  0,
+		},
+		"http date": {
+			resp: withRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)),
+			min:  50 * time.Second,
+			max:  time.Minute,
+		},
+		"past http date": {
+			resp: withRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)),
+			min:  0,
+			max:  0,
+		},
+		"invalid header": {
+			attempt: 2,
+			resp:    withRetryAfter("soon"),
+			min:     1,
+			max:     retryInitialBackoff << 2,
+		},
+		"no response": {
+			attempt: 1,
+			min:     1,
+			max:     retryInitialBackoff << 1,
+		},
+		"capped": {
+			attempt: 20,
+			resp:    &http.Response{Header: http.Header{}},
+			min:     1,
+			max:     retryMaxBackoff,
+		},
+		"overflow": {
+			attempt: 100,
+			min:     1,
+			max:     retryMaxBackoff,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := retryBackoff(tt.attempt, tt.resp)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("got backoff %s, want between %s and %s", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultVerificationRetries = 5
+	verificationInitialBackoff = 5 * time.Second
+	verificationMaxBackoff     = 2 * time.Minute
+)
+
+// isRetryableVerificationError reports whether a WebResource.Insert failure is
+// likely to succeed on a later attempt. Google's verifier is eventually
+// consistent, so a token it cannot find yet may show up shortly. Rate limiting
+// is already retried by retryTransport, and other errors are returned
+// immediately.
+func isRetryableVerificationError(err error) bool {
+	return classifyAPIError(err) == apiErrorVerificationFailed
+}
+
+// retryVerification calls fn until it succeeds, returns an error that is not
+// retryable, or has been retried the given number of times.
+func retryVerification(ctx context.Context, retries int, fn func() error) error {
+	backoff := verificationInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !isRetryableVerificationError(err) {
+			return err
+		}
+		tflog.Debug(ctx, "Verification failed, retrying", map[string]any{
+			"attempt": attempt + 1,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > verificationMaxBackoff {
+			backoff = verificationMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsRetryableVerificationError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"token not found": {
+			err:  &googleapi.Error{Code: http.StatusBadRequest, Message: "The verification token could not be found."},
+			want: true,
+		},
+		"wrapped verification failure": {
+			err:  fmt.Errorf("inserting: %w", &googleapi.Error{Code: http.StatusBadRequest, Message: "Verification failed"}),
+			want: true,
+		},
+		// Retried by retryTransport instead.
+		"rate limited": {
+			err: &googleapi.Error{Code: http.StatusTooManyRequests},
+		},
+		"quota exceeded": {
+			err: &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}},
+		},
+		"unavailable": {
+			err: &googleapi.Error{Code: http.StatusServiceUnavailable},
+		},
+		"permission denied": {
+			err: &googleapi.Error{Code: http.StatusForbidden},
+		},
+		"other bad request": {
+			err: &googleapi.Error{Code: http.StatusBadRequest, Message: "Invalid site identifier"},
+		},
+		"not an API error": {
+			err: errors.New("connection reset"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isRetryableVerificationError(tt.err); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryVerificationStopsOnOtherErrors(t *testing.T) {
+	calls := 0
+	err := retryVerification(context.Background(), defaultVerificationRetries, func() error {
+		calls++
+		return &googleapi.Error{Code: http.StatusTooManyRequests}
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	sitev1 "google.golang.org/api/siteverification/v1"
+)
+
+// getVerificationToken asks Google for the token that verifies site with method.
+func getVerificationToken(ctx context.Context, client *sitev1.Service, siteType, identifier, method string) (string, error) {
+	greq := &sitev1.SiteVerificationWebResourceGettokenRequest{
+		Site: &sitev1.SiteVerificationWebResourceGettokenRequestSite{
+			Identifier: identifier,
+			Type:       siteType,
+		},
+		VerificationMethod: method,
+	}
+	tflog.Trace(ctx, "Request", map[string]any{
+		"request": greq,
+	})
+
+	callResp, err := client.WebResource.GetToken(greq).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+
+	tflog.Trace(ctx, "Response", map[string]any{
+		"status": callResp.ServerResponse.HTTPStatusCode,
+		"header": callResp.ServerResponse.Header,
+		"token":  callResp.Token,
+	})
+	return callResp.Token, nil
+}
+
+const (
+	// txtTokenPrefix starts every DNS_TXT verification token.
+	txtTokenPrefix = "google-site-verification="
+	// maxTXTStringLength is the longest character string a TXT record value may hold.
+	maxTXTStringLength = 255
+)
+
+var txtTokenValueRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// unquoteTXT returns the text of a TXT record value, joining the character
+// strings of a value that is quoted and split into several strings, as in
+// "abc" "def". Unquoted values are returned as is.
+func unquoteTXT(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return value
+	}
+	var b strings.Builder
+	inQuote, escaped := false, false
+	for _, c := range value {
+		switch {
+		case escaped:
+			b.WriteRune(c)
+			escaped = false
+		case inQuote && c == '\\':
+			escaped = true
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// quoteTXT formats text as a TXT record value, split into quoted character
+// strings of at most maxTXTStringLength characters.
+func quoteTXT(text string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	var chunks []string
+	for {
+		chunk := text
+		if len(chunk) > maxTXTStringLength {
+			chunk = chunk[:maxTXTStringLength]
+		}
+		chunks = append(chunks, fmt.Sprintf(`"%s"`, escape.Replace(chunk)))
+		text = text[len(chunk):]
+		if text == "" {
+			return strings.Join(chunks, " ")
+		}
+	}
+}
+
+// normalizeToken returns the canonical form of a token for method, so that
+// differently quoted or formatted spellings of the same token compare equal.
+func normalizeToken(method, token string) string {
+	switch method {
+	case "DNS_TXT":
+		token = unquoteTXT(token)
+		if !strings.HasPrefix(token, txtTokenPrefix) {
+			token = txtTokenPrefix + token
+		}
+		return token
+	case "DNS_CNAME":
+		return strings.Join(strings.Fields(token), " ")
+	case "META":
+		return metaTagContent(token)
+	}
+	return strings.TrimSpace(token)
+}
+
+// tokensEqual reports whether two tokens for method are semantically equal.
+func tokensEqual(method, a, b string) bool {
+	return normalizeToken(method, a) == normalizeToken(method, b)
+}
+
+// tokenValidator checks that a token is well formed for the verification
+// method configured alongside it.
+type tokenValidator struct{}
+
+func (v tokenValidator) Description(ctx context.Context) string {
+	return "value must be a verification token for the configured verification method"
+}
+
+func (v tokenValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v tokenValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var method types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("verification_method"), &method)...)
+
+	if resp.Diagnostics.HasError() || method.IsUnknown() {
+		return
+	}
+
+	if method.IsNull() {
+		method = types.StringValue("DNS_TXT")
+	}
+
+	token := req.ConfigValue.ValueString()
+	var problem string
+	switch normalized := normalizeToken(method.ValueString(), token); {
+	case strings.TrimSpace(token) == "":
+		problem = "The token must not be empty."
+	case method.ValueString() == "DNS_TXT" && !txtTokenValueRegex.MatchString(strings.TrimPrefix(normalized, txtTokenPrefix)):
+		problem = fmt.Sprintf("A DNS_TXT token has the form %s<value>, optionally quoted.", txtTokenPrefix)
+	case method.ValueString() == "DNS_CNAME":
+		if _, _, err := splitCNAMEToken(normalized); err != nil {
+			problem = "A DNS_CNAME token has the form \"<host label> <target>\"."
+		}
+	}
+	if problem != "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid verification token",
+			fmt.Sprintf("%s Got %q.", problem, token),
+		)
+	}
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUnquoteTXT(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		want  string
+	}{
+		"unquoted":          {value: "google-site-verification=abc", want: "google-site-verification=abc"},
+		"surrounding space": {value: "  abc \n", want: "abc"},
+		"quoted":            {value: `"google-site-verification=abc"`, want: "google-site-verification=abc"},
+		"multi segment":     {value: `"abc" "def"`, want: "abcdef"},
+		"adjacent segments": {value: `"abc""def"`, want: "abcdef"},
+		"escaped quote":     {value: `"say \"hi\""`, want: `say "hi"`},
+		"escaped backslash": {value: `"a\\b"`, want: `a\b`},
+		"quoted space":      {value: `"a b" "c"`, want: "a bc"},
+		"empty":             {value: `""`, want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := unquoteTXT(tt.value); got != tt.want {
+				t.Errorf("unquoteTXT(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	long := strings.Repeat("a", maxTXTStringLength)
+
+	tests := map[string]struct {
+		text string
+		want string
+	}{
+		"short":         {text: "abc", want: `"abc"`},
+		"empty":         {text: "", want: `""`},
+		"escaped quote": {text: `say "hi"`, want: `"say \"hi\""`},
+		"backslash":     {text: `a\b`, want: `"a\\b"`},
+		"exactly 255":   {text: long, want: `"` + long + `"`},
+		"256":           {text: long + "b", want: `"` + long + `" "b"`},
+		"510":           {text: long + long, want: `"` + long + `" "` + long + `"`},
+		"511":           {text: long + long + "c", want: `"` + long + `" "` + long + `" "c"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := quoteTXT(tt.text)
+			if got != tt.want {
+				t.Errorf("quoteTXT(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if back := unquoteTXT(got); back != tt.text {
+				t.Errorf("unquoteTXT(quoteTXT(%q)) = %q", tt.text, back)
+			}
+		})
+	}
+}
+
+func TestTokensEqual(t *testing.T) {
+	const token = "google-site-verification=abc123_-XYZ"
+
+	tests := map[string]struct {
+		method string
+		a, b   string
+		want   bool
+	}{
+		"txt identical":         {method: "DNS_TXT", a: token, b: token, want: true},
+		"txt unprefixed":        {method: "DNS_TXT", a: token, b: "abc123_-XYZ", want: true},
+		"txt quoted":            {method: "DNS_TXT", a: token, b: `"` + token + `"`, want: true},
+		"txt quoted unprefixed": {method: "DNS_TXT", a: token, b: `"abc123_-XYZ"`, want: true},
+		"txt split":             {method: "DNS_TXT", a: token, b: `"google-site-verification=" "abc123_-XYZ"`, want: true},
+		"txt different":         {method: "DNS_TXT", a: token, b: "google-site-verification=other", want: false},
+		"txt case":              {method: "DNS_TXT", a: token, b: strings.ToLower(token), want: false},
+		"cname spacing":         {method: "DNS_CNAME", a: "abc gv-xyz.dv.googlehosted.com", b: "  abc   gv-xyz.dv.googlehosted.com\n", want: true},
+		"cname different":       {method: "DNS_CNAME", a: "abc gv-xyz.dv.googlehosted.com", b: "abd gv-xyz.dv.googlehosted.com", want: false},
+		"meta element":          {method: "META", a: `<meta name="google-site-verification" content="abc" />`, b: "abc", want: true},
+		"file spacing":          {method: "FILE", a: "google123.html", b: " google123.html ", want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tokensEqual(tt.method, tt.a, tt.b); got != tt.want {
+				t.Errorf("tokensEqual(%q, %q, %q) = %t, want %t", tt.method, tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTXTRecordRoundTrip(t *testing.T) {
+	data := &SiteVerificationResourceModel{
+		VerificationMethod: types.StringValue("DNS_TXT"),
+		SiteIdentifier:     types.StringValue("example.com"),
+		Token:              types.StringValue(`"abc123"`),
+	}
+	record, err := data.DNSRecordSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := record.Rrdatas[0], `"google-site-verification=abc123"`; got != want {
+		t.Errorf("got rrdata %q, want %q", got, want)
+	}
+	// Cloud DNS may return the value split differently.
+	if !txtValuesEqual(record.Rrdatas[0], `"google-site-verification=" "abc123"`) {
+		t.Error("expected split rrdata to equal the record value")
+	}
+}
+
+func TestSplitCNAMEToken(t *testing.T) {
+	tests := map[string]struct {
+		token      string
+		wantLabel  string
+		wantTarget string
+		wantErr    bool
+	}{
+		"valid":        {token: "abc gv-xyz.dv.googlehosted.com", wantLabel: "abc", wantTarget: "gv-xyz.dv.googlehosted.com"},
+		"extra spaces": {token: " abc \t gv-xyz.dv.googlehosted.com ", wantLabel: "abc", wantTarget: "gv-xyz.dv.googlehosted.com"},
+		"one field":    {token: "abc", wantErr: true},
+		"three fields": {token: "abc def gv-xyz.dv.googlehosted.com", wantErr: true},
+		"empty":        {token: "", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			label, target, err := splitCNAMEToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if label != tt.wantLabel || target != tt.wantTarget {
+				t.Errorf("got %q %q, want %q %q", label, target, tt.wantLabel, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestTokenValidator(t *testing.T) {
+	tests := map[string]struct {
+		method  string
+		token   string
+		wantErr bool
+	}{
+		"txt prefixed":       {method: "DNS_TXT", token: "google-site-verification=abc123"},
+		"txt unprefixed":     {method: "DNS_TXT", token: "abc123"},
+		"txt quoted":         {method: "DNS_TXT", token: `"google-site-verification=abc123"`},
+		"txt split":          {method: "DNS_TXT", token: `"google-site-verification=" "abc123"`},
+		"default method":     {token: "google-site-verification=abc123"},
+		"txt empty":          {method: "DNS_TXT", token: "  ", wantErr: true},
+		"txt invalid":        {method: "DNS_TXT", token: "google-site-verification=abc 123", wantErr: true},
+		"txt prefix only":    {method: "DNS_TXT", token: "google-site-verification=", wantErr: true},
+		"cname valid":        {method: "DNS_CNAME", token: "abc gv-xyz.dv.googlehosted.com"},
+		"cname one field":    {method: "DNS_CNAME", token: "abc", wantErr: true},
+		"cname three fields": {method: "DNS_CNAME", token: "abc def ghi", wantErr: true},
+		"file":               {method: "FILE", token: "google123.html"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			values := map[string]tftypes.Value{
+				"site_identifier": tftypes.NewValue(tftypes.String, "example.com"),
+				"token":           tftypes.NewValue(tftypes.String, tt.token),
+			}
+			if tt.method != "" {
+				values["verification_method"] = tftypes.NewValue(tftypes.String, tt.method)
+			}
+			resp := &validator.StringResponse{}
+			tokenValidator{}.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("token"),
+				Config:      siteVerificationConfig(t, values),
+				ConfigValue: types.StringValue(tt.token),
+			}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("got error %t, want %t: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	storagev1 "google.golang.org/api/storage/v1"
+)
+
+const (
+	verificationURLPollInterval = 5 * time.Second
+	verificationURLTimeout      = 5 * time.Minute
+)
+
+// webRootBackend is implemented by the places a verification file can be
+// published to so that it is served from the root of a site.
+type webRootBackend interface {
+	PutFile(ctx context.Context, name string, content []byte) error
+	DeleteFile(ctx context.Context, name string) error
+}
+
+// WebRootModel describes the web_root attribute of the site verification resource.
+type WebRootModel struct {
+	GCSBucket    types.String `tfsdk:"gcs_bucket"`
+	ObjectPrefix types.String `tfsdk:"object_prefix"`
+}
+
+// newWebRootBackend returns the backend configured for the given web_root object.
+func newWebRootBackend(ctx context.Context, clients *SiteVerificationClients, obj types.Object) (webRootBackend, error) {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, fmt.Errorf("web_root must be set when using the FILE verification method")
+	}
+	var webRoot WebRootModel
+	if diags := obj.As(ctx, &webRoot, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("failed to parse web_root: %v", diags)
+	}
+	if !webRoot.GCSBucket.IsNull() && webRoot.GCSBucket.ValueString() != "" {
+		return &gcsWebRoot{
+			client: clients.Storage,
+			bucket: webRoot.GCSBucket.ValueString(),
+			prefix: webRoot.ObjectPrefix.ValueString(),
+		}, nil
+	}
+	return nil, fmt.Errorf("web_root does not configure a supported backend")
+}
+
+// gcsWebRoot publishes verification files to a Cloud Storage bucket.
+type gcsWebRoot struct {
+	client *storagev1.Service
+	bucket string
+	prefix string
+}
+
+func (g *gcsWebRoot) objectName(name string) string {
+	return strings.TrimPrefix(path.Join(g.prefix, name), "/")
+}
+
+func (g *gcsWebRoot) PutFile(ctx context.Context, name string, content []byte) error {
+	obj := &storagev1.Object{
+		Name:         g.objectName(name),
+		ContentType:  "text/html; charset=utf-8",
+		CacheControl: "no-cache",
+	}
+	tflog.Trace(ctx, "Uploading verification file", map[string]any{
+		"bucket": g.bucket,
+		"object": obj.Name,
+	})
+	_, err := g.client.Objects.Insert(g.bucket, obj).
+		Media(bytes.NewReader(content)).
+		Context(ctx).
+		Do()
+	return err
+}
+
+func (g *gcsWebRoot) DeleteFile(ctx context.Context, name string) error {
+	tflog.Trace(ctx, "Deleting verification file", map[string]any{
+		"bucket": g.bucket,
+		"object": g.objectName(name),
+	})
+	return g.client.Objects.Delete(g.bucket, g.objectName(name)).Context(ctx).Do()
+}
+
+// verificationFileContent returns the body Google expects to find in the
+// verification file named by token.
+func verificationFileContent(token string) []byte {
+	return []byte(fmt.Sprintf("google-site-verification: %s", token))
+}
+
+// verificationFileURL returns the URL the verification file will be served from.
+func verificationFileURL(site, token string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(site, "/"), token)
+}
+
+// waitForURLContent polls url until its body contains content or the timeout expires.
+func waitForURLContent(ctx context.Context, url string, content []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, verificationURLTimeout)
+	defer cancel()
+	ticker := time.NewTicker(verificationURLPollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		lastErr = checkURLContent(ctx, url, content)
+		if lastErr == nil {
+			return nil
+		}
+		tflog.Trace(ctx, "Verification content not yet served", map[string]any{
+			"url":   url,
+			"error": lastErr.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s to be served: %w", url, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+func checkURLContent(ctx context.Context, url string, content []byte) error {
+	body, err := fetchURL(ctx, url)
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(body, content) {
+		return fmt.Errorf("response does not contain the verification token")
+	}
+	return nil
+}
+
+// fetchURL returns up to the first megabyte of the body served at url.
+func fetchURL(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
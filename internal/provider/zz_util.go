@@ -14,14 +14,6 @@ import (
 	sitev1 "google.golang.org/api/siteverification/v1"
 )
 
-func stringSliceToListValue(vals []string) (basetypes.ListValue, diag.Diagnostics) {
-	var listVals []attr.Value
-	for _, val := range vals {
-		listVals = append(listVals, types.StringValue(val))
-	}
-	return types.ListValue(types.StringType, listVals)
-}
-
 func listValueToStringSlice(ctx context.Context, list basetypes.ListValue) ([]string, error) {
 	var vals []string
 	for _, elem := range list.Elements() {
@@ -47,10 +39,14 @@ func parseOwnersFromResponse(resp *sitev1.SiteVerificationWebResourceResource) (
 }
 
 func parseOwnersFromData(ctx context.Context, data *SiteVerificationResourceModel) ([]string, error) {
-	if data.Owners.IsNull() {
+	if data.Owners.IsNull() || data.Owners.IsUnknown() {
 		return nil, nil
 	}
-	return listValueToStringSlice(ctx, data.Owners)
+	var owners []string
+	if diags := data.Owners.ElementsAs(ctx, &owners, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to read owners: %v", diags)
+	}
+	return owners, nil
 }
 
 func decodeID(id string) (string, error) {
@@ -63,3 +59,52 @@ func forceDot(str string) string {
 	}
 	return fmt.Sprintf("%s.", str)
 }
+
+// splitCNAMEToken splits a DNS_CNAME token of the form "<host label> <target>",
+// as returned by the API, into its label and target.
+func splitCNAMEToken(token string) (string, string, error) {
+	fields := strings.Fields(token)
+	if len(fields) != 2 {
+		return "", "", fmt.Errorf("invalid DNS_CNAME token %q: expected a host label and a target", token)
+	}
+	return fields[0], fields[1], nil
+}
+
+// parseCNAMEToken splits a DNS_CNAME token into the fully qualified host name
+// of the record and its target. The label of the token is relative to site.
+func parseCNAMEToken(token, site string) (string, string, error) {
+	label, target, err := splitCNAMEToken(token)
+	if err != nil {
+		return "", "", err
+	}
+	host := forceDot(label)
+	if !strings.HasSuffix(host, forceDot(site)) {
+		host = fmt.Sprintf("%s.%s", label, forceDot(site))
+	}
+	return host, forceDot(target), nil
+}
+
+// txtValuesEqual reports whether two TXT record values hold the same text once
+// quoting and splitting into character strings are ignored.
+func txtValuesEqual(a, b string) bool {
+	return unquoteTXT(a) == unquoteTXT(b)
+}
+
+func containsTXTValue(values []string, value string) bool {
+	for _, v := range values {
+		if txtValuesEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsOwner reports whether owners includes owner, ignoring case.
+func containsOwner(owners []string, owner string) bool {
+	for _, o := range owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}